@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	internal "it.davquar/gitrelnoty/internal/ghrelnoty"
+	"it.davquar/gitrelnoty/internal/store"
+)
+
+// command is a subcommand working on the database, given its args positional
+// arguments.
+type command struct {
+	usage string
+	args  int
+	run   func(db *store.Store, flags *flag.FlagSet, args []string) error
+	flags func(flags *flag.FlagSet)
+}
+
+var commands = map[string]command{
+	"mute": {
+		usage: "mute [--until TIME | --for DURATION] REPOSITORY",
+		args:  1,
+		flags: func(flags *flag.FlagSet) {
+			flags.String("until", "", "Mute until the given time (RFC 3339 or YYYY-MM-DD)")
+			flags.String("for", "", "Mute for the given duration, like 72h")
+		},
+		run: func(db *store.Store, flags *flag.FlagSet, args []string) error {
+			until, err := internal.ParseUntil(flags.Lookup("until").Value.String(), flags.Lookup("for").Value.String(), time.Now())
+			if err != nil {
+				return err
+			}
+			return db.Mute(args[0], until)
+		},
+	},
+	"unmute": {
+		usage: "unmute REPOSITORY",
+		args:  1,
+		run: func(db *store.Store, _ *flag.FlagSet, args []string) error {
+			return db.Unmute(args[0])
+		},
+	},
+	"ignore": {
+		usage: "ignore REPOSITORY VERSION",
+		args:  2,
+		run: func(db *store.Store, _ *flag.FlagSet, args []string) error {
+			return db.Ignore(args[0], args[1])
+		},
+	},
+	"unignore": {
+		usage: "unignore REPOSITORY VERSION",
+		args:  2,
+		run: func(db *store.Store, _ *flag.FlagSet, args []string) error {
+			return db.Unignore(args[0], args[1])
+		},
+	},
+	"mutes": {
+		usage: "mutes",
+		run: func(db *store.Store, _ *flag.FlagSet, _ []string) error {
+			return listMutes(db)
+		},
+	},
+}
+
+// runCommand runs the subcommand with the given name and arguments, opening the
+// database of the configuration.
+func runCommand(name string, args []string) error {
+	cmd := commands[name]
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := flags.String("config-path", os.Getenv("GHRELNOTY_CONFIG_PATH"), "Path to ghrelnotify's YAML configuration file")
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ghrelnoty %s\n", cmd.usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != cmd.args {
+		flags.Usage()
+		return fmt.Errorf("wrong number of arguments")
+	}
+
+	if *configPath == "" {
+		return fmt.Errorf("config path not given: use --config-path or GHRELNOTY_CONFIG_PATH")
+	}
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	db, err := store.Open(config.DBPath)
+	if errors.Is(err, bolt.ErrTimeout) {
+		return fmt.Errorf("%w: if ghrelnoty is running, use its HTTP API", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	return cmd.run(&db, flags, flags.Args())
+}
+
+// listMutes prints the repositories with silenced notifications.
+func listMutes(db *store.Store) error {
+	mutes, err := db.ListMutes()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(mutes))
+	for name := range mutes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tMUTED\tIGNORED")
+	for _, name := range names {
+		m := mutes[name]
+		muted := "no"
+		if m.Muted {
+			muted = "yes"
+		} else if time.Now().Before(m.Until) {
+			muted = "until " + m.Until.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, muted, strings.Join(m.Ignored, ", "))
+	}
+	return w.Flush()
+}
@@ -24,6 +24,12 @@ func main() {
 }
 
 func run() error {
+	if len(os.Args) > 1 {
+		if _, ok := commands[os.Args[1]]; ok {
+			return runCommand(os.Args[1], os.Args[2:])
+		}
+	}
+
 	var configPath string
 	flag.StringVar(&configPath, "config-path", "", "Path to ghrelnotify's YAML configuration file")
 	flag.Parse()
@@ -39,6 +45,15 @@ func run() error {
 	if err != nil {
 		return err
 	}
+	if config.GitHubToken == "" {
+		config.GitHubToken = os.Getenv("GHRELNOTY_GITHUB_TOKEN")
+	}
+	if config.WebhookSecret == "" {
+		config.WebhookSecret = os.Getenv("GHRELNOTY_WEBHOOK_SECRET")
+	}
+	if config.APIToken == "" {
+		config.APIToken = os.Getenv("GHRELNOTY_API_TOKEN")
+	}
 
 	svc, err := internal.New(config)
 	if err != nil {
@@ -52,6 +67,12 @@ func run() error {
 
 	go func(chan<- error) {
 		http.Handle("/metrics", promhttp.Handler())
+		if config.APIToken != "" {
+			http.Handle("/api/", svc.APIHandler())
+		}
+		if config.WebhookSecret != "" {
+			http.Handle("/webhook", svc.WebhookHandler())
+		}
 		metricsServer := &http.Server{
 			Addr:              fmt.Sprintf(":%d", config.MetricsPort),
 			ReadHeaderTimeout: 3 * time.Second,
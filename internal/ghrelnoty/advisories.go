@@ -0,0 +1,134 @@
+package ghrelnoty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/go-github/v68/github"
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// AdvisoryLister is implemented by Releasers that can list the security
+// advisories of their repository.
+type AdvisoryLister interface {
+	GetAdvisories(context.Context) ([]release.Release, error)
+}
+
+// GetAdvisories gets the published security advisories of the repository, as
+// Releases of kind release.KindAdvisory.
+func (r GitHubRepository) GetAdvisories(ctx context.Context) ([]release.Release, error) {
+	author, repo := r.SeparateName()
+	opts := &github.ListRepositorySecurityAdvisoriesOptions{
+		State:     "published",
+		Sort:      "published",
+		Direction: "desc",
+	}
+
+	advisories, _, err := r.Client.SecurityAdvisories.ListRepositorySecurityAdvisories(ctx, author, repo, opts)
+	if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+		return nil, rateLimitErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Name, err)
+	}
+
+	releases := make([]release.Release, 0, len(advisories))
+	for _, a := range advisories {
+		advisory := &release.Advisory{
+			ID:       a.GetGHSAID(),
+			Severity: a.GetSeverity(),
+			Summary:  a.GetSummary(),
+		}
+		for _, id := range a.Identifiers {
+			if id.GetType() == "CVE" {
+				advisory.CVEs = append(advisory.CVEs, id.GetValue())
+			}
+		}
+		if len(advisory.CVEs) == 0 && a.GetCVEID() != "" {
+			advisory.CVEs = append(advisory.CVEs, a.GetCVEID())
+		}
+		for _, v := range a.Vulnerabilities {
+			patched := v.GetPatchedVersions()
+			if patched == "" {
+				patched = v.GetFirstPatchedVersion().GetIdentifier()
+			}
+			advisory.Vulnerabilities = append(advisory.Vulnerabilities, release.Vulnerability{
+				Package:  v.GetPackage().GetName(),
+				Affected: v.GetVulnerableVersionRange(),
+				Patched:  patched,
+			})
+		}
+
+		releases = append(releases, release.Release{
+			Kind:        release.KindAdvisory,
+			Project:     repo,
+			Author:      author,
+			Version:     a.GetGHSAID(),
+			Description: a.GetDescription(),
+			URL:         a.GetHTMLURL(),
+			PublishedAt: a.GetPublishedAt().Time,
+			Advisory:    advisory,
+		})
+	}
+	return releases, nil
+}
+
+// checkAdvisories notifies the security advisories of the repository that were not
+// seen before, recording them as seen once notified. The first time a repository is
+// checked, its advisories are only recorded as seen.
+func (s Service) checkAdvisories(ctx context.Context, repo Releaser) error {
+	lister, ok := repo.(AdvisoryLister)
+	if !ok || !repo.Config().Advisories {
+		return nil
+	}
+	name := repo.Config().Name
+
+	advisories, err := lister.GetAdvisories(ctx)
+	if err != nil {
+		metrics.CannotGetRelease()
+		slog.ErrorContext(ctx, "can't get advisories", slog.String("repo", name), slog.Any("err", err))
+		return err
+	}
+
+	repoKey, err := s.Store.ResolveKey(name)
+	if err != nil {
+		metrics.DBError()
+		return err
+	}
+	known, err := s.Store.MarkSeen(store.AdvisoriesBucket, repoKey)
+	if err != nil {
+		metrics.DBError()
+		return err
+	}
+
+	var errs []error
+	for _, a := range advisories {
+		key := repoKey + "/" + a.Advisory.ID
+		seen, err := s.Store.Seen(store.AdvisoriesBucket, key)
+		if err != nil {
+			metrics.DBError()
+			errs = append(errs, err)
+			continue
+		}
+		if seen {
+			continue
+		}
+
+		if known {
+			metrics.AdvisoryFound()
+			if err := s.notify(repo.Config(), a); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if _, err := s.Store.MarkSeen(store.AdvisoriesBucket, key); err != nil {
+			metrics.DBError()
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+package ghrelnoty
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+type dummyAdvisoryReleaser struct {
+	dummyReleaser
+	advisories []release.Release
+	err        error
+}
+
+func (r *dummyAdvisoryReleaser) GetAdvisories(_ context.Context) ([]release.Release, error) {
+	return r.advisories, r.err
+}
+
+func advisory(id string) release.Release {
+	return release.Release{
+		Kind:     release.KindAdvisory,
+		Version:  id,
+		Advisory: &release.Advisory{ID: id, Severity: "high"},
+	}
+}
+
+func TestCheckAdvisories(t *testing.T) {
+	s, notifiers := newTestService(t, "email", "security")
+	notifications := notifiers["security"]
+
+	repo := &dummyAdvisoryReleaser{
+		dummyReleaser: dummyReleaser{RepositoryConfig{
+			Name:                "author/name",
+			Destination:         "email",
+			Advisories:          true,
+			SecurityDestination: "security",
+		}},
+		advisories: []release.Release{advisory("GHSA-old")},
+	}
+
+	if err := s.checkAdvisories(context.Background(), repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 0 {
+		t.Fatal("expected existing advisories not to be notified at the first check")
+	}
+
+	repo.advisories = append(repo.advisories, advisory("GHSA-new"))
+	if err := s.checkAdvisories(context.Background(), repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if r := <-notifications; r.Advisory.ID != "GHSA-new" {
+		t.Fatalf("expected GHSA-new, got %s", r.Advisory.ID)
+	}
+
+	if err := s.checkAdvisories(context.Background(), repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 0 {
+		t.Fatal("expected seen advisories not to be notified again")
+	}
+	repo.advisories = append(repo.advisories, advisory("GHSA-retry"))
+	delete(s.Notifiers, "security")
+	if err := s.checkAdvisories(context.Background(), repo); err == nil {
+		t.Fatal("expected error without the security notifier")
+	}
+	s.Notifiers["security"] = notifications
+	if err := s.checkAdvisories(context.Background(), repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := <-notifications; r.Advisory.ID != "GHSA-retry" {
+		t.Fatalf("expected GHSA-retry after the failed notification, got %s", r.Advisory.ID)
+	}
+}
+
+func TestWorkKeepsGoingOnAdvisoryErrors(t *testing.T) {
+	s, notifiers := newTestService(t, "email")
+	s.Releasers = []Releaser{
+		&dummyAdvisoryReleaser{
+			dummyReleaser: dummyReleaser{RepositoryConfig{Name: "author/failing", Destination: "email", Advisories: true}},
+			err:           errors.New("not found"),
+		},
+		dummyReleaser{RepositoryConfig{Name: "author/name", Destination: "email"}},
+	}
+
+	c := make(chan error)
+	go s.Work(c)
+
+	select {
+	case err, ok := <-c:
+		if ok {
+			t.Fatalf("expected no errors on the channel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Work to finish despite the advisories error")
+	}
+	if len(notifiers["email"]) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifiers["email"]))
+	}
+}
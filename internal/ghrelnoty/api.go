@@ -0,0 +1,172 @@
+package ghrelnoty
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// versionStatus is how a repository with a deployed version is described by the API.
+type versionStatus struct {
+	Repository string    `json:"repository"`
+	Current    string    `json:"current_version"`
+	Latest     string    `json:"latest_version"`
+	Major      int       `json:"major"`
+	Minor      int       `json:"minor"`
+	Patch      int       `json:"patch"`
+	Superseded time.Time `json:"superseded"`
+	DaysBehind int       `json:"days_behind"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// muteStatus is how the Mute of a repository is described by the API.
+type muteStatus struct {
+	Repository string    `json:"repository"`
+	Muted      bool      `json:"muted"`
+	Until      time.Time `json:"until"`
+	Ignored    []string  `json:"ignored"`
+}
+
+// APIHandler returns the handler of the HTTP API, mounted under /api/ when
+// Config.APIToken is set. All the endpoints require the token as bearer.
+// GET /api/versions lists the repositories with a deployed version, with how far
+// behind their latest release they are as of the last check, and GET /api/mutes
+// the repositories with silenced notifications. POST /api/mute, /api/unmute,
+// /api/ignore and /api/unignore change them.
+func (s Service) APIHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/versions", s.authorized(s.handleVersions))
+	mux.Handle("GET /api/mutes", s.authorized(s.handleMutes))
+	mux.Handle("POST /api/mute", s.authorized(s.handleMute))
+	mux.Handle("POST /api/unmute", s.authorized(s.handleUnmute))
+	mux.Handle("POST /api/ignore", s.authorized(s.handleIgnore))
+	mux.Handle("POST /api/unignore", s.authorized(s.handleUnignore))
+	return mux
+}
+
+// authorized wraps the given handler, rejecting requests without Config.APIToken
+// as bearer token, or all of them if it's not set.
+func (s Service) authorized(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.Config.APIToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.Config.APIToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	})
+}
+
+func (s Service) handleVersions(w http.ResponseWriter, _ *http.Request) {
+	statuses, err := s.Store.ListStatus()
+	if err != nil {
+		metrics.DBError()
+		slog.Error("can't read status from db", slog.Any("err", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	versions := make([]versionStatus, 0, len(statuses))
+	for name, st := range statuses {
+		versions = append(versions, versionStatus{
+			Repository: name,
+			Current:    st.Current,
+			Latest:     st.Latest,
+			Major:      st.Major,
+			Minor:      st.Minor,
+			Patch:      st.Patch,
+			Superseded: st.Superseded,
+			DaysBehind: release.Behind{Superseded: st.Superseded}.Days(now),
+			CheckedAt:  st.CheckedAt,
+		})
+	}
+	slices.SortFunc(versions, func(a, b versionStatus) int {
+		return strings.Compare(a.Repository, b.Repository)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(versions); err != nil {
+		slog.Warn("can't write api response", slog.Any("err", err))
+	}
+}
+
+func (s Service) handleMutes(w http.ResponseWriter, _ *http.Request) {
+	mutes, err := s.Store.ListMutes()
+	if err != nil {
+		metrics.DBError()
+		slog.Error("can't read mutes from db", slog.Any("err", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	statuses := make([]muteStatus, 0, len(mutes))
+	for name, m := range mutes {
+		statuses = append(statuses, muteStatus{
+			Repository: name,
+			Muted:      m.Muted,
+			Until:      m.Until,
+			Ignored:    m.Ignored,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b muteStatus) int {
+		return strings.Compare(a.Repository, b.Repository)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		slog.Warn("can't write api response", slog.Any("err", err))
+	}
+}
+
+// handleMute mutes the given repository until the given time or for the given
+// duration, or until unmuted if neither is set.
+func (s Service) handleMute(w http.ResponseWriter, r *http.Request) {
+	until, err := ParseUntil(r.FormValue("until"), r.FormValue("for"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.updateMute(w, r, false, func(repo string, _ string) error {
+		return s.Store.Mute(repo, until)
+	})
+}
+
+func (s Service) handleUnmute(w http.ResponseWriter, r *http.Request) {
+	s.updateMute(w, r, false, func(repo string, _ string) error {
+		return s.Store.Unmute(repo)
+	})
+}
+
+func (s Service) handleIgnore(w http.ResponseWriter, r *http.Request) {
+	s.updateMute(w, r, true, s.Store.Ignore)
+}
+
+func (s Service) handleUnignore(w http.ResponseWriter, r *http.Request) {
+	s.updateMute(w, r, true, s.Store.Unignore)
+}
+
+// updateMute applies the given update with the repository, and the version if
+// needed, of the request.
+func (s Service) updateMute(w http.ResponseWriter, r *http.Request, needsVersion bool, update func(repo string, version string) error) {
+	repo, version := r.FormValue("repository"), r.FormValue("version")
+	if repo == "" || (needsVersion && version == "") {
+		http.Error(w, "missing repository or version", http.StatusBadRequest)
+		return
+	}
+
+	if err := update(repo, version); err != nil {
+		metrics.DBError()
+		slog.Error("can't store mute in db", slog.String("repo", repo), slog.Any("err", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,30 +3,157 @@ package ghrelnoty
 import (
 	"fmt"
 	"log/slog"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
 	dstsmtp "it.davquar/gitrelnoty/internal/ghrelnoty/destinations/smtp"
+	"it.davquar/gitrelnoty/pkg/release"
 )
 
 // Config holds the app's configuration.
 type Config struct {
-	LogLevel     slog.Level                   `yaml:"log_level"`
-	DBPath       string                       `yaml:"db_path"`
-	CheckEvery   time.Duration                `yaml:"check_every"`
-	SleepBetween time.Duration                `yaml:"sleep_between"`
-	Repositories []RepositoryConfig           `yaml:"repositories"`
-	Destinations map[string]DestinationConfig `yaml:"destinations"`
-	MetricsPort  int                          `yaml:"metrics_port"`
+	LogLevel      slog.Level                   `yaml:"log_level"`
+	DBPath        string                       `yaml:"db_path"`
+	GitHubToken   string                       `yaml:"github_token"`
+	CheckEvery    time.Duration                `yaml:"check_every"`
+	SleepBetween  time.Duration                `yaml:"sleep_between"`
+	MaxReleases   int                          `yaml:"max_releases"`
+	Repositories  []RepositoryConfig           `yaml:"repositories"`
+	Providers     []ProviderConfig             `yaml:"providers"`
+	Destinations  map[string]DestinationConfig `yaml:"destinations"`
+	MetricsPort   int                          `yaml:"metrics_port"`
+	WebhookSecret string                       `yaml:"webhook_secret"`
+	APIToken      string                       `yaml:"api_token"`
+	Releases      ReleaseFilters               `yaml:"releases"`
+	Keywords      []KeywordRule                `yaml:"keywords"`
+	Groups        map[string]GroupConfig       `yaml:"groups"`
+}
+
+// GroupConfig holds the settings shared by the repositories and providers that
+// name the group in their Group: its Releases filters apply after the global ones,
+// and before the ones of each repository.
+type GroupConfig struct {
+	Releases ReleaseFilters `yaml:"releases"`
 }
 
 // RepositoryConfig holds data needed to identify the repository to watch
 // and the destination to send notifications to.
+// If Owner is set instead of Name, all the repositories of the owner that
+// pass the OwnerFilters are watched, each with the rest of the configuration.
+// Prereleases is one of exclude (default), include or only; prereleases are sent
+// to PrereleaseDestination, if set.
+// RequireAssets is a glob pattern: releases are considered only once they have a
+// matching asset. MinAge holds newer releases back until they are old enough and
+// still the latest one, so that pulled or quickly superseded releases aren't notified.
+// DetectMutations enables notifications for moved tags, replaced assets and
+// deleted releases, at the cost of one more request per check.
+// Advisories enables notifications for security advisories, at the cost of one more
+// request per check. Both are sent to SecurityDestination, if set.
+// Lifecycle enables notifications for archived, renamed, transferred or deprecated
+// repositories, at the cost of one more request per check. With FollowRenames, the
+// stored data of renamed repositories is moved under their new name.
+// Backports enables notifications for releases with a version lower than the latest
+// one, but published after it; they are only logged and counted otherwise.
+// Releases filters the releases to notify, on top of the global filters and the ones
+// of Group, if set, while Thresholds skip the notification of releases that still
+// update the stored baseline.
+// DeployedVersion, if set, is compared with the releases to tell how far behind it is.
+// Tracks, if set, split the releases in lines processed on their own.
+// Keywords are applied to the releases after the global ones.
+// Registry is the URL of the registry for types other than github, like the Go
+// module proxy for goproxy (default https://proxy.golang.org).
+// Email adds recipients to the emails of the repository, or replaces them.
 type RepositoryConfig struct {
-	Type        string `yaml:"type"`
-	Name        string `yaml:"name"`
-	Destination string `yaml:"destination"`
+	Type                  string                    `yaml:"type"`
+	Name                  string                    `yaml:"name"`
+	Owner                 string                    `yaml:"owner"`
+	Destination           string                    `yaml:"destination"`
+	Prereleases           string                    `yaml:"prereleases"`
+	PrereleaseDestination string                    `yaml:"prerelease_destination"`
+	RequireAssets         string                    `yaml:"require_assets"`
+	MinAge                time.Duration             `yaml:"min_age"`
+	DetectMutations       bool                      `yaml:"detect_mutations"`
+	Advisories            bool                      `yaml:"advisories"`
+	SecurityDestination   string                    `yaml:"security_destination"`
+	Lifecycle             bool                      `yaml:"lifecycle"`
+	FollowRenames         bool                      `yaml:"follow_renames"`
+	Backports             bool                      `yaml:"backports"`
+	Registry              string                    `yaml:"registry"`
+	Group                 string                    `yaml:"group"`
+	Releases              ReleaseFilters            `yaml:"releases"`
+	Tracks                []Track                   `yaml:"tracks"`
+	Keywords              []KeywordRule             `yaml:"keywords"`
+	Email                 destinations.EmailRouting `yaml:"email"`
+	OwnerFilters          `yaml:",inline"`
+	VersionConfig         `yaml:",inline"`
+	DeployedVersion       `yaml:",inline"`
+	Thresholds            `yaml:",inline"`
+
+	// References are set by providers that find the repository in use in some files.
+	References []release.Reference `yaml:"-"`
+	// Track is the name of the track the configuration is restricted to, if any.
+	Track string `yaml:"-"`
+}
+
+// OwnerFilters narrow down the repositories listed for an owner.
+// By default archived repositories and forks are skipped.
+type OwnerFilters struct {
+	Archived  bool     `yaml:"archived"`
+	Forks     bool     `yaml:"forks"`
+	Topics    []string `yaml:"topics"`
+	NameRegex string   `yaml:"name_regex"`
+
+	nameRegex *regexp.Regexp
+}
+
+// Validate returns an error if the OwnerFilters can't be used, and compiles their
+// NameRegex otherwise.
+func (f *OwnerFilters) Validate() error {
+	if f.NameRegex == "" {
+		return nil
+	}
+	re, err := regexp.Compile(f.NameRegex)
+	if err != nil {
+		return fmt.Errorf("invalid name regex %s: %w", f.NameRegex, err)
+	}
+	f.nameRegex = re
+	return nil
+}
+
+// ProviderConfig holds data needed to build a dynamic list of repositories
+// to watch, and the destination to send their notifications to.
+// Include and Exclude are glob patterns matched against repo-owner/repo-name.
+// Registry is passed on to the repositories that are not on GitHub, as Group is,
+// and Releases filters the releases of all the repositories, like
+// RepositoryConfig.Releases, as Email routes their emails.
+type ProviderConfig struct {
+	Type        string   `yaml:"type"`
+	User        string   `yaml:"user"`
+	Source      string   `yaml:"source"`
+	Paths       []string `yaml:"paths"`
+	Destination string   `yaml:"destination"`
+	Include     []string `yaml:"include"`
+	Exclude     []string `yaml:"exclude"`
+	Registry    string   `yaml:"registry"`
+	Group       string   `yaml:"group"`
+
+	Releases ReleaseFilters            `yaml:"releases"`
+	Email    destinations.EmailRouting `yaml:"email"`
+
+	Advisories          bool   `yaml:"advisories"`
+	SecurityDestination string `yaml:"security_destination"`
+	Lifecycle           bool   `yaml:"lifecycle"`
+
+	OwnerFilters `yaml:",inline"`
+
+	// template is the configuration of the repositories listed for an owner entry
+	// of Config.Repositories, if the provider comes from one.
+	template *RepositoryConfig
 }
 
 // DestinationConfig holds specific notification settings.
@@ -36,6 +163,48 @@ type DestinationConfig struct {
 	Config interface{}
 }
 
+// WantsRelease returns true if a release, prerelease or not, has to be
+// considered according to Prereleases.
+func (r RepositoryConfig) WantsRelease(prerelease bool) bool {
+	switch r.Prereleases {
+	case "include":
+		return true
+	case "only":
+		return prerelease
+	default:
+		return !prerelease
+	}
+}
+
+// DestinationFor returns the destination for the given release.
+func (r RepositoryConfig) DestinationFor(rel release.Release) string {
+	security := rel.Kind == release.KindAdvisory || rel.Kind == release.KindMutated || slices.Contains(rel.Labels, securityLabel)
+	if security && r.SecurityDestination != "" {
+		return r.SecurityDestination
+	}
+	if rel.Prerelease && r.PrereleaseDestination != "" {
+		return r.PrereleaseDestination
+	}
+	return r.Destination
+}
+
+// ReferencesFor returns the References of the repository, flagged as outdated
+// compared to the given release, and with the number of releases they are behind
+// it, among the given ones.
+func (r RepositoryConfig) ReferencesFor(rel release.Release, releases []release.Release) []release.Reference {
+	if len(r.References) == 0 {
+		return nil
+	}
+
+	references := make([]release.Reference, 0, len(r.References))
+	for _, ref := range r.References {
+		ref.Outdated = isOutdated(ref.Version, rel.Version)
+		ref.Behind = releasesBehind(ref.Version, rel, releases)
+		references = append(references, ref)
+	}
+	return references
+}
+
 // SeparateName returns a pair of repo-owner and repo-name, from a string
 // like repo-owner/repo-name
 func (r RepositoryConfig) SeparateName() (string, string) {
@@ -43,6 +212,53 @@ func (r RepositoryConfig) SeparateName() (string, string) {
 	return repo[0], repo[1]
 }
 
+// Matches returns true if the given repo-owner/repo-name matches at least one
+// Include pattern (or there are none) and no Exclude pattern.
+func (p ProviderConfig) Matches(name string) bool {
+	for _, pattern := range p.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+
+	if len(p.Include) == 0 {
+		return true
+	}
+	for _, pattern := range p.Include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// repository returns the configuration of a repository listed by the provider.
+// For owner entries of Config.Repositories, it's a copy of the entry with the given
+// name.
+func (p ProviderConfig) repository(name string) RepositoryConfig {
+	if p.template != nil {
+		repo := *p.template
+		repo.Name = name
+		repo.Owner = ""
+		repo.OwnerFilters = OwnerFilters{}
+		repo.Tracks = slices.Clone(repo.Tracks)
+		repo.Keywords = slices.Clone(repo.Keywords)
+		return repo
+	}
+
+	return RepositoryConfig{
+		Type:                "github",
+		Name:                name,
+		Destination:         p.Destination,
+		Group:               p.Group,
+		Advisories:          p.Advisories,
+		SecurityDestination: p.SecurityDestination,
+		Lifecycle:           p.Lifecycle,
+		Releases:            p.Releases,
+		Email:               p.Email,
+	}
+}
+
 // UnmarshalYAML implements custom unmarshaling logic to produce the
 // appropriate DestinationConfig.Config implementation based on DestinationConfig.Type.
 func (dc *DestinationConfig) UnmarshalYAML(value *yaml.Node) error {
@@ -2,10 +2,13 @@ package ghrelnoty
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 
 	"gopkg.in/yaml.v3"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
 	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations/smtp"
+	"it.davquar/gitrelnoty/pkg/release"
 )
 
 func TestSeparateName(t *testing.T) {
@@ -53,6 +56,73 @@ destinations:
 	}
 }
 
+func TestDestinationsYAMLTemplates(t *testing.T) {
+	y := `
+destinations:
+  mydstname:
+    type: smtp
+    config:
+      to: to@test.test
+      subject_template: "{{ .Repo }} {{ .Version }}"
+      html_template_file: email.html
+`
+
+	var c Config
+	if err := yaml.Unmarshal([]byte(y), &c); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	d, ok := c.Destinations["mydstname"].Config.(*smtp.Destination)
+	if !ok {
+		t.Fatalf("expected destination of type SMTP, got %s", reflect.TypeOf(c.Destinations["mydstname"].Config))
+	}
+	if !slices.Equal(d.To, destinations.Addresses{"to@test.test"}) || d.Subject != "{{ .Repo }} {{ .Version }}" || d.HTMLFile != "email.html" {
+		t.Fatalf("unexpected destination %+v", d)
+	}
+}
+
+func TestRecipientsYAML(t *testing.T) {
+	y := `
+repositories:
+  - name: author/name
+    email:
+      to: owner@test.test, other@test.test
+      replace: true
+destinations:
+  mydstname:
+    type: smtp
+    config:
+      to:
+        - Team <team@test.test>
+        - ops@test.test
+      cc: lead@test.test
+`
+
+	var c Config
+	if err := yaml.Unmarshal([]byte(y), &c); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	d, ok := c.Destinations["mydstname"].Config.(*smtp.Destination)
+	if !ok {
+		t.Fatalf("expected destination of type SMTP, got %s", reflect.TypeOf(c.Destinations["mydstname"].Config))
+	}
+	if !slices.Equal(d.To, destinations.Addresses{"Team <team@test.test>", "ops@test.test"}) || !slices.Equal(d.Cc, destinations.Addresses{"lead@test.test"}) || d.Bcc != nil {
+		t.Fatalf("unexpected recipients %v, %v, %v", d.To, d.Cc, d.Bcc)
+	}
+
+	routing := c.Repositories[0].Email
+	if !routing.Replace || !slices.Equal(routing.To, destinations.Addresses{"owner@test.test, other@test.test"}) {
+		t.Fatalf("unexpected routing %+v", routing)
+	}
+	if err := routing.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := (destinations.EmailRouting{Cc: destinations.Addresses{"not an address"}}).Validate(); err == nil {
+		t.Fatal("expected error with invalid address")
+	}
+}
+
 func TestDestinationsYAMLUnmarshalUnknownNotifier(t *testing.T) {
 	y := `
 destinations:
@@ -67,3 +137,79 @@ destinations:
 		t.Fatalf("expected unmarshal error due to unknown destination type, got Config=%v", c)
 	}
 }
+
+func TestProviderMatches(t *testing.T) {
+	p := ProviderConfig{
+		Include: []string{"kubernetes-sigs/*", "davquar/*"},
+		Exclude: []string{"*/*-archive"},
+	}
+
+	cases := map[string]bool{
+		"kubernetes-sigs/kind":        true,
+		"davquar/ghrelnoty":           true,
+		"davquar/old-archive":         false,
+		"firefly-iii/firefly-iii":     false,
+		"kubernetes-sigs/kubebuilder": true,
+	}
+
+	for name, expected := range cases {
+		if got := p.Matches(name); got != expected {
+			t.Errorf("%s: expected %t, got %t", name, expected, got)
+		}
+	}
+
+	if !(ProviderConfig{}).Matches("any/repo") {
+		t.Fatal("expected a provider without patterns to match everything")
+	}
+}
+
+func TestOwnerYAMLUnmarshal(t *testing.T) {
+	y := `
+repositories:
+  - type: github
+    owner: kubernetes-sigs
+    destination: email
+    forks: true
+    topics: [k8s]
+    name_regex: ^kind
+`
+
+	var c Config
+	err := yaml.Unmarshal([]byte(y), &c)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	r := c.Repositories[0]
+	if r.Owner != "kubernetes-sigs" || !r.Forks || r.Archived || r.NameRegex != "^kind" || len(r.Topics) != 1 {
+		t.Fatalf("unexpected owner entry: %+v", r)
+	}
+}
+
+func TestWantsRelease(t *testing.T) {
+	cases := map[string][2]bool{
+		"":        {true, false},
+		"exclude": {true, false},
+		"include": {true, true},
+		"only":    {false, true},
+	}
+
+	for value, expected := range cases {
+		r := RepositoryConfig{Prereleases: value}
+		if r.WantsRelease(false) != expected[0] || r.WantsRelease(true) != expected[1] {
+			t.Errorf("prereleases %q: expected [release, prerelease] %v, got [%t, %t]",
+				value, expected, r.WantsRelease(false), r.WantsRelease(true))
+		}
+	}
+}
+
+func TestDestinationFor(t *testing.T) {
+	r := RepositoryConfig{Destination: "email", PrereleaseDestination: "rc"}
+
+	if dst := r.DestinationFor(release.Release{}); dst != "email" {
+		t.Fatalf("expected email for releases, got %s", dst)
+	}
+	if dst := r.DestinationFor(release.Release{Prerelease: true}); dst != "rc" {
+		t.Fatalf("expected rc for prereleases, got %s", dst)
+	}
+}
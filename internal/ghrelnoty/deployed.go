@@ -0,0 +1,135 @@
+package ghrelnoty
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// DeployedVersion is the version of a repository currently in use, given as
+// CurrentVersion or read from CurrentVersionFile at every check, so that it can
+// follow a deployment repository. CurrentVersionRegex is applied to the content
+// of the file, and its first capture group (or the whole match) is the version.
+type DeployedVersion struct {
+	CurrentVersion      string `yaml:"current_version"`
+	CurrentVersionFile  string `yaml:"current_version_file"`
+	CurrentVersionRegex string `yaml:"current_version_regex"`
+
+	regex *regexp.Regexp
+}
+
+// Validate returns an error if the DeployedVersion can't be used, and compiles its
+// CurrentVersionRegex otherwise. It must be called before Resolve.
+func (d *DeployedVersion) Validate() error {
+	if d.CurrentVersion != "" && d.CurrentVersionFile != "" {
+		return fmt.Errorf("current_version and current_version_file are mutually exclusive")
+	}
+	re, err := regexp.Compile(d.CurrentVersionRegex)
+	if err != nil {
+		return fmt.Errorf("invalid current version regex %s: %w", d.CurrentVersionRegex, err)
+	}
+	d.regex = re
+	return nil
+}
+
+// Resolve returns the deployed version, or an empty string if it's not configured.
+func (d DeployedVersion) Resolve() (string, error) {
+	if d.CurrentVersionFile == "" {
+		return d.CurrentVersion, nil
+	}
+
+	content, err := os.ReadFile(d.CurrentVersionFile)
+	if err != nil {
+		return "", fmt.Errorf("read current version: %w", err)
+	}
+
+	version := string(content)
+	if d.CurrentVersionRegex != "" {
+		if d.regex == nil {
+			return "", errors.New("current version regex not validated")
+		}
+
+		match := d.regex.FindStringSubmatch(version)
+		if match == nil {
+			return "", fmt.Errorf("current version regex doesn't match %s", d.CurrentVersionFile)
+		}
+		version = match[0]
+		if len(match) > 1 {
+			version = match[1]
+		}
+	}
+	return strings.TrimSpace(version), nil
+}
+
+// versionsBehind returns how far the deployed version is from the given release,
+// counting the newer releases among the given ones up to it. It returns false if
+// the versions can't be compared as semver.
+func versionsBehind(deployed string, rel release.Release, releases []release.Release) (release.Behind, bool) {
+	d, err := semver.Parse(deployed)
+	if err != nil {
+		return release.Behind{}, false
+	}
+	latest, err := semver.Parse(rel.Version)
+	if err != nil {
+		return release.Behind{}, false
+	}
+
+	behind := release.Behind{Current: deployed}
+	for _, r := range releases {
+		v, err := semver.Parse(r.Version)
+		if err != nil || v.Compare(d) <= 0 || v.Compare(latest) > 0 {
+			continue
+		}
+
+		switch {
+		case v.Major != d.Major:
+			behind.Major++
+		case v.Minor != d.Minor:
+			behind.Minor++
+		default:
+			behind.Patch++
+		}
+
+		if !r.PublishedAt.IsZero() && (behind.Superseded.IsZero() || r.PublishedAt.Before(behind.Superseded)) {
+			behind.Superseded = r.PublishedAt
+		}
+	}
+	return behind, true
+}
+
+// reportBehind updates the metrics and the stored Status of the repository, with
+// how far the deployed version is from the latest release.
+func (s Service) reportBehind(repo RepositoryConfig, key string, deployed string, latest release.Release, releases []release.Release) error {
+	behind, ok := versionsBehind(deployed, latest, releases)
+	if !ok {
+		slog.Debug("can't compare deployed version", slog.String("repo", repo.Name), slog.String("deployed", deployed), slog.String("latest", latest.Version))
+		return nil
+	}
+
+	now := time.Now()
+	metrics.SetVersionsBehind(repo.Name, repo.Track, behind.Major, behind.Minor, behind.Patch, behind.Days(now))
+
+	err := s.Store.SetStatus(key, store.Status{
+		Current:    deployed,
+		Latest:     latest.Version,
+		Major:      behind.Major,
+		Minor:      behind.Minor,
+		Patch:      behind.Patch,
+		Superseded: behind.Superseded,
+		CheckedAt:  now.UTC(),
+	})
+	if err != nil {
+		metrics.DBError()
+		return fmt.Errorf("store status of %s: %w", repo.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package ghrelnoty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestDeployedVersionResolve(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(file, []byte("image:\n  repository: grafana/grafana\n  tag: 11.1.4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := DeployedVersion{CurrentVersionFile: file, CurrentVersionRegex: `tag: (\S+)`}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	version, err := d.Resolve()
+	if err != nil || version != "11.1.4" {
+		t.Fatalf("expected 11.1.4, got %s, %v", version, err)
+	}
+
+	if err := (&DeployedVersion{CurrentVersion: "1.0.0", CurrentVersionFile: file}).Validate(); err == nil {
+		t.Fatal("expected error with both version and file")
+	}
+}
+
+func TestVersionsBehind(t *testing.T) {
+	superseded := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	releases := []release.Release{
+		{Version: "v2.1.0"},
+		{Version: "v2.0.0"},
+		{Version: "v1.5.0", PublishedAt: superseded.AddDate(0, 1, 0)},
+		{Version: "v1.4.2", PublishedAt: superseded},
+		{Version: "v1.4.1"},
+	}
+
+	behind, ok := versionsBehind("v1.4.1", releases[0], releases)
+	if !ok {
+		t.Fatal("expected versions to be comparable")
+	}
+	expected := release.Behind{Current: "v1.4.1", Major: 2, Minor: 1, Patch: 1, Superseded: superseded}
+	if behind != expected {
+		t.Fatalf("expected %+v, got %+v", expected, behind)
+	}
+
+	if behind, _ := versionsBehind("v1.4.1", releases[2], releases); behind.Releases() != 2 {
+		t.Fatalf("expected 2 releases behind v1.5.0, got %+v", behind)
+	}
+	if _, ok := versionsBehind("main", releases[0], releases); ok {
+		t.Fatal("expected a branch not to be comparable")
+	}
+}
+
+func TestProcessReportsBehind(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	s.Config.APIToken = "token"
+	notifications := notifiers["chan"]
+
+	repo := RepositoryConfig{
+		Name:            "author/name",
+		Destination:     "chan",
+		DeployedVersion: DeployedVersion{CurrentVersion: "v1.0.0"},
+	}
+	releases := []release.Release{{Version: "v1.1.0", Tag: "v1.1.0"}, {Version: "v1.0.1", Tag: "v1.0.1"}}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := <-notifications
+	if r.Behind == nil || r.Behind.Minor != 1 || r.Behind.Patch != 1 {
+		t.Fatalf("unexpected behind %+v", r.Behind)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/versions", nil)
+	s.APIHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 without token, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	req.Header.Set("Authorization", "Bearer token")
+	s.APIHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var versions []versionStatus
+	if err := json.NewDecoder(rec.Body).Decode(&versions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Repository != "author/name" ||
+		versions[0].Current != "v1.0.0" || versions[0].Latest != "v1.1.0" || versions[0].Minor != 1 {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+}
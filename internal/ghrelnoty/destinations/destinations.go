@@ -0,0 +1,81 @@
+// Package destinations holds what is shared by the destinations notifications are
+// sent to.
+package destinations
+
+import (
+	"fmt"
+	"net/mail"
+
+	"gopkg.in/yaml.v3"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// Notification is what destinations are given to notify: a release, and the
+// repository it's notified for.
+type Notification struct {
+	Release    release.Release
+	Repository Repository
+}
+
+// Repository describes the configured repository a release is notified for.
+// Email routes the emails of the repository, for the destinations sending them.
+type Repository struct {
+	Type        string
+	Name        string
+	Destination string
+	Track       string
+	Email       EmailRouting
+}
+
+// Addresses is a list of email addresses, possibly with display names. In YAML it
+// can be a single string, a comma-separated list, or a sequence of them.
+type Addresses []string
+
+// UnmarshalYAML implements yaml.Unmarshaler to also accept a single string.
+func (a *Addresses) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value != "" {
+			*a = Addresses{value.Value}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Parse returns the parsed addresses.
+func (a Addresses) Parse() ([]*mail.Address, error) {
+	var parsed []*mail.Address
+	for _, s := range a {
+		list, err := mail.ParseAddressList(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s: %w", s, err)
+		}
+		parsed = append(parsed, list...)
+	}
+	return parsed, nil
+}
+
+// EmailRouting holds the recipients of the emails of a repository, added to the
+// ones of the destination or, if Replace is set, replacing them.
+type EmailRouting struct {
+	To      Addresses `yaml:"to"`
+	Cc      Addresses `yaml:"cc"`
+	Bcc     Addresses `yaml:"bcc"`
+	Replace bool      `yaml:"replace"`
+}
+
+// Validate returns an error if some addresses can't be parsed.
+func (r EmailRouting) Validate() error {
+	for _, addresses := range []Addresses{r.To, r.Cc, r.Bcc} {
+		if _, err := addresses.Parse(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package smtp
+
+import (
+	_ "embed"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations/templates"
+)
+
+var (
+	//go:embed templates/common.tmpl
+	commonTemplate string
+	//go:embed templates/subject.tmpl
+	subjectTemplate string
+	//go:embed templates/text.tmpl
+	textTemplate string
+	//go:embed templates/html.tmpl
+	htmlTemplate string
+)
+
+// defaults are the built-in templates of emails.
+var defaults = templates.Defaults{
+	Common:  commonTemplate,
+	Subject: subjectTemplate,
+	Text:    textTemplate,
+	HTML:    htmlTemplate,
+}
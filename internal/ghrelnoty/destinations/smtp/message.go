@@ -0,0 +1,139 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// message holds what is needed to build an email.
+type message struct {
+	From     *mail.Address
+	To       []*mail.Address
+	Cc       []*mail.Address
+	Subject  string
+	Date     time.Time
+	Priority string
+	Text     string
+	HTML     string
+}
+
+// Bytes returns the email as defined by RFC 5322 and MIME, without a Bcc header:
+// the subject is encoded as per RFC 2047 if needed, and the bodies are
+// quoted-printable. If HTML is set, the email is multipart/alternative, with the
+// plain text part first.
+func (m message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", m.From.String())
+	if len(m.To) > 0 {
+		writeHeader(&buf, "To", addressList(m.To))
+	}
+	if len(m.Cc) > 0 {
+		writeHeader(&buf, "Cc", addressList(m.Cc))
+	}
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "Date", m.Date.Format(time.RFC1123Z))
+	id, err := messageID(m.From.Address)
+	if err != nil {
+		return nil, err
+	}
+	writeHeader(&buf, "Message-ID", id)
+	writeHeader(&buf, "MIME-Version", "1.0")
+	buf.WriteString(m.Priority)
+
+	if m.HTML == "" {
+		writeHeader(&buf, "Content-Type", mime.FormatMediaType("text/plain", map[string]string{"charset": "utf-8"}))
+		writeHeader(&buf, "Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, m.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	writeHeader(&buf, "Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()}))
+	buf.WriteString("\r\n")
+	for _, part := range []struct {
+		mediaType string
+		body      string
+	}{
+		{"text/plain", m.Text},
+		{"text/html", m.HTML},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(part.mediaType, map[string]string{"charset": "utf-8"})},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create %s part: %w", part.mediaType, err)
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// addressList returns the given addresses as the value of a header like To.
+func addressList(addresses []*mail.Address) string {
+	list := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		list = append(list, a.String())
+	}
+	return strings.Join(list, ", ")
+}
+
+// maxLineLength is the recommended maximum length of the lines of an email.
+const maxLineLength = 78
+
+// writeHeader writes the given header, folding it at spaces to keep its lines
+// within maxLineLength, when possible.
+func writeHeader(buf *bytes.Buffer, name string, value string) {
+	line := name + ":"
+	for _, word := range strings.Split(value, " ") {
+		if len(line)+1+len(word) > maxLineLength {
+			buf.WriteString(line + "\r\n")
+			line = ""
+		}
+		line += " " + word
+	}
+	buf.WriteString(line + "\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("encode body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("encode body: %w", err)
+	}
+	return nil
+}
+
+// messageID returns a new unique Message-ID, with the domain of the given address.
+func messageID(address string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate message id: %w", err)
+	}
+
+	domain := "localhost"
+	if i := strings.LastIndex(address, "@"); i >= 0 && i < len(address)-1 {
+		domain = address[i+1:]
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain), nil
+}
@@ -0,0 +1,81 @@
+package smtp
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+)
+
+// recipients holds the parsed recipients of an email.
+type recipients struct {
+	To  []*mail.Address
+	Cc  []*mail.Address
+	Bcc []*mail.Address
+}
+
+// recipients returns the recipients of the email, according to the Destination
+// and the given routing of the repository.
+func (d Destination) recipients(routing destinations.EmailRouting) (recipients, error) {
+	to, cc, bcc := d.To, d.Cc, d.Bcc
+	if routing.Replace {
+		to, cc, bcc = routing.To, routing.Cc, routing.Bcc
+	} else {
+		to = append(slices.Clip(to), routing.To...)
+		cc = append(slices.Clip(cc), routing.Cc...)
+		bcc = append(slices.Clip(bcc), routing.Bcc...)
+	}
+
+	var (
+		rcpts recipients
+		err   error
+	)
+	if rcpts.To, err = to.Parse(); err != nil {
+		return recipients{}, err
+	}
+	if rcpts.Cc, err = cc.Parse(); err != nil {
+		return recipients{}, err
+	}
+	if rcpts.Bcc, err = bcc.Parse(); err != nil {
+		return recipients{}, err
+	}
+	if len(rcpts.envelope()) == 0 {
+		return recipients{}, fmt.Errorf("no recipients")
+	}
+	return rcpts, nil
+}
+
+// envelope returns the unique addresses of all the recipients, without display names.
+func (r recipients) envelope() []string {
+	var addresses []string
+	for _, list := range [][]*mail.Address{r.To, r.Cc, r.Bcc} {
+		for _, a := range list {
+			if !slices.Contains(addresses, a.Address) {
+				addresses = append(addresses, a.Address)
+			}
+		}
+	}
+	return addresses
+}
+
+// RejectedRecipientsError is returned when the email was sent, but some of the
+// recipients were rejected by the server.
+type RejectedRecipientsError struct {
+	Rejected map[string]error
+}
+
+func (e *RejectedRecipientsError) Error() string {
+	addresses := make([]string, 0, len(e.Rejected))
+	for address := range e.Rejected {
+		addresses = append(addresses, address)
+	}
+	slices.Sort(addresses)
+
+	reasons := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		reasons = append(reasons, fmt.Sprintf("%s (%v)", address, e.Rejected[address]))
+	}
+	return "recipients rejected: " + strings.Join(reasons, ", ")
+}
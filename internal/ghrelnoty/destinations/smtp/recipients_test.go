@@ -0,0 +1,179 @@
+package smtp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	smtpmock "github.com/mocktools/go-smtp-mock/v2"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func startServer(t *testing.T, rejected ...string) *smtpmock.Server {
+	t.Helper()
+	server := smtpmock.New(smtpmock.ConfigurationAttr{
+		MultipleRcptto:          true,
+		BlacklistedRcpttoEmails: rejected,
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("cannot start smtp mock server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("smtp mock server error while closing: %v", err)
+		}
+	})
+	return server
+}
+
+func rcptTo(msg smtpmock.Message) []string {
+	var addresses []string
+	for _, rcpt := range msg.RcpttoRequestResponse() {
+		addresses = append(addresses, strings.TrimPrefix(rcpt[0], "RCPT TO:"))
+	}
+	return addresses
+}
+
+func TestRecipients(t *testing.T) {
+	d := Destination{
+		To:  destinations.Addresses{"Team <team@test.test>, ops@test.test"},
+		Cc:  destinations.Addresses{"lead@test.test"},
+		Bcc: destinations.Addresses{"audit@test.test"},
+	}
+
+	cases := []struct {
+		name    string
+		routing *destinations.EmailRouting
+		to      string
+		cc      string
+		rcpt    []string
+	}{
+		{
+			name: "destination",
+			to:   `"Team" <team@test.test>, <ops@test.test>`,
+			cc:   "<lead@test.test>",
+			rcpt: []string{"team@test.test", "ops@test.test", "lead@test.test", "audit@test.test"},
+		},
+		{
+			name:    "added",
+			routing: &destinations.EmailRouting{To: destinations.Addresses{"owner@test.test"}, Bcc: destinations.Addresses{"ops@test.test"}},
+			to:      `"Team" <team@test.test>, <ops@test.test>, <owner@test.test>`,
+			cc:      "<lead@test.test>",
+			rcpt:    []string{"team@test.test", "ops@test.test", "owner@test.test", "lead@test.test", "audit@test.test"},
+		},
+		{
+			name:    "replaced",
+			routing: &destinations.EmailRouting{To: destinations.Addresses{"owner@test.test"}, Replace: true},
+			to:      "<owner@test.test>",
+			rcpt:    []string{"owner@test.test"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var routing destinations.EmailRouting
+			if c.routing != nil {
+				routing = *c.routing
+			}
+
+			rcpts, err := d.recipients(routing)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := addressList(rcpts.To); got != c.to {
+				t.Errorf("expected to '%s', got '%s'", c.to, got)
+			}
+			if got := addressList(rcpts.Cc); got != c.cc {
+				t.Errorf("expected cc '%s', got '%s'", c.cc, got)
+			}
+			if got := rcpts.envelope(); strings.Join(got, " ") != strings.Join(c.rcpt, " ") {
+				t.Errorf("expected recipients %v, got %v", c.rcpt, got)
+			}
+		})
+	}
+
+	if _, err := (Destination{}).recipients(destinations.EmailRouting{}); err == nil {
+		t.Error("expected error without recipients")
+	}
+}
+
+func TestNotifyRecipients(t *testing.T) {
+	server := startServer(t)
+	d := Destination{
+		From: "from@test.test",
+		To:   destinations.Addresses{"team@test.test", "ops@test.test"},
+		Cc:   destinations.Addresses{"lead@test.test"},
+		Bcc:  destinations.Addresses{"audit@test.test"},
+		Host: "127.0.0.1",
+		Port: strconv.Itoa(server.PortNumber()),
+	}
+
+	if err := d.Init(); err != nil {
+		t.Fatalf("unexpected error initializing destination: %v", err)
+	}
+
+	n := destinations.Notification{
+		Release:    release.Release{Project: "dummy-project", Author: "dummy-author", Version: "v1.2.3"},
+		Repository: destinations.Repository{Email: destinations.EmailRouting{To: destinations.Addresses{"owner@test.test"}}},
+	}
+	if err := d.Notify(n); err != nil {
+		t.Fatalf("unexpected error sending email: %v", err)
+	}
+
+	msgs := server.MessagesAndPurge()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message in a single transaction, got %d", len(msgs))
+	}
+	expected := []string{"<team@test.test>", "<ops@test.test>", "<owner@test.test>", "<lead@test.test>", "<audit@test.test>"}
+	if got := rcptTo(msgs[0]); strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected recipients %v, got %v", expected, got)
+	}
+
+	header, _ := parseEmail(t, msgs[0].MsgRequest())
+	if got := header.Get("To"); got != "<team@test.test>, <ops@test.test>, <owner@test.test>" {
+		t.Errorf("unexpected to '%s'", got)
+	}
+	if got := header.Get("Cc"); got != "<lead@test.test>" {
+		t.Errorf("unexpected cc '%s'", got)
+	}
+	if got := header.Get("Bcc"); got != "" {
+		t.Errorf("expected no bcc header, got '%s'", got)
+	}
+}
+
+func TestNotifyRejectedRecipients(t *testing.T) {
+	server := startServer(t, "ops@test.test", "audit@test.test")
+	d := Destination{
+		From: "from@test.test",
+		To:   destinations.Addresses{"team@test.test", "ops@test.test"},
+		Bcc:  destinations.Addresses{"audit@test.test"},
+		Host: "127.0.0.1",
+		Port: strconv.Itoa(server.PortNumber()),
+	}
+	if err := d.Init(); err != nil {
+		t.Fatalf("unexpected error initializing destination: %v", err)
+	}
+
+	n := destinations.Notification{Release: release.Release{Project: "dummy-project", Author: "dummy-author", Version: "v1.2.3"}}
+	err := d.Notify(n)
+	var rejected *RejectedRecipientsError
+	if !errors.As(err, &rejected) || len(rejected.Rejected) != 2 || rejected.Rejected["ops@test.test"] == nil || rejected.Rejected["audit@test.test"] == nil {
+		t.Fatalf("expected ops and audit to be rejected, got %v", err)
+	}
+	if msgs := server.MessagesAndPurge(); len(msgs) != 1 || !msgs[0].IsConsistent() {
+		t.Fatalf("expected the message to be sent to the other recipients, got %d", len(msgs))
+	}
+
+	d.To = destinations.Addresses{"ops@test.test"}
+	err = d.Notify(n)
+	if err == nil || errors.As(err, &rejected) || !strings.Contains(err.Error(), "ops@test.test") || !strings.Contains(err.Error(), "audit@test.test") {
+		t.Fatalf("expected all recipients to be rejected, got %v", err)
+	}
+	for _, msg := range server.MessagesAndPurge() {
+		if msg.MsgRequest() != "" {
+			t.Fatal("expected no message to be sent")
+		}
+	}
+}
@@ -1,91 +1,171 @@
 package smtp
 
 import (
-	"bytes"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/mail"
 	"net/smtp"
+	"time"
 
-	"github.com/yuin/goldmark"
-	goldmarkext "github.com/yuin/goldmark/extension"
-	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations/templates"
 	"it.davquar/gitrelnoty/pkg/release"
 )
 
 // Destination holds the configuration for the SMTP destination.
+// Emails are sent to To, Cc and Bcc in a single transaction, plus the recipients
+// routed by the repository, if any. The subject and body of emails are rendered
+// with the templates in Config, or the built-in ones; if HTML is set, emails have
+// both a plain text and an HTML body. Init must be called before Notify.
 type Destination struct {
-	From     string `yaml:"from"`
-	To       string `yaml:"to"`
-	Host     string `yaml:"host"`
-	Port     string `yaml:"port"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
-	HTML     bool   `yaml:"html"`
+	From             string                 `yaml:"from"`
+	To               destinations.Addresses `yaml:"to"`
+	Cc               destinations.Addresses `yaml:"cc"`
+	Bcc              destinations.Addresses `yaml:"bcc"`
+	Host             string                 `yaml:"host"`
+	Port             string                 `yaml:"port"`
+	Username         string                 `yaml:"username"`
+	Password         string                 `yaml:"password"`
+	HTML             bool                   `yaml:"html"`
+	templates.Config `yaml:",inline"`
+
+	set *templates.Set
 }
 
-// Notify sends an email to Destination, to announce a new Release of the given repo.
-func (d Destination) Notify(release release.Release) error {
-	subject := fmt.Sprintf("New release: %s %s", release.Repo(), release.Version)
-	msg := []byte(fmt.Sprintf("From: %s\r\n"+
-		"To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"\r\n"+
-		"%s\r\n", d.From, d.To, subject, makeBody(release, d.HTML)))
-
-	err := smtp.SendMail(d.Host+":"+d.Port, d.auth(), d.From, []string{d.To}, msg)
-
-	return err
-}
+// Init validates the addresses and parses the templates of the Destination.
+func (d *Destination) Init() error {
+	if _, err := mail.ParseAddress(d.From); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if err := (destinations.EmailRouting{To: d.To, Cc: d.Cc, Bcc: d.Bcc}).Validate(); err != nil {
+		return err
+	}
 
-// auth returns smtp.Auth if username and password are set, otherwise nil indicating NOAUTH
-func (d Destination) auth() smtp.Auth {
-	if d.Username == "" && d.Password == "" {
-		return nil
+	set, err := templates.Parse(d.Config, defaults)
+	if err != nil {
+		return err
 	}
-	return smtp.PlainAuth("", d.From, d.Password, d.Host)
+	d.set = set
+	return nil
 }
 
-func makeBody(r release.Release, html bool) string {
-	if html {
-		return htmlContent(r)
+// Notify sends an email to Destination, to announce the release of the notification.
+func (d Destination) Notify(n destinations.Notification) error {
+	if d.set == nil {
+		return errors.New("destination not initialized")
 	}
-	return plaintextContent(r)
-}
 
-func plaintextContent(r release.Release) string {
-	return fmt.Sprintf(`GHRelNoty
----------
+	from, err := mail.ParseAddress(d.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	rcpts, err := d.recipients(n.Repository.Email)
+	if err != nil {
+		return err
+	}
 
-New release for %s/%s: %s
+	r := n.Release
+	m := message{From: from, To: rcpts.To, Cc: rcpts.Cc, Date: time.Now(), Priority: priorityHeaders(r.Priority)}
+	if r.Kind == release.KindMutated || r.Kind == release.KindAdvisory {
+		m.Priority = priorityHeaders(release.PriorityUrgent)
+	}
+	data := templates.NewData(n)
+	if m.Subject, err = d.set.Subject(data); err != nil {
+		return err
+	}
+	if m.Text, err = d.set.Text(data); err != nil {
+		return err
+	}
+	if d.HTML {
+		if m.HTML, err = d.set.HTML(data); err != nil {
+			return err
+		}
+	}
 
-%s
+	msg, err := m.Bytes()
+	if err != nil {
+		return err
+	}
 
-URL: %s`, r.Author, r.Project, r.Version,
-		r.Description,
-		r.URL)
+	return d.send(from.Address, rcpts.envelope(), msg)
 }
 
-func htmlContent(r release.Release) string {
-	md := goldmark.New(
-		goldmark.WithExtensions(goldmarkext.GFM),
-		goldmark.WithRendererOptions(
-			goldmarkhtml.WithUnsafe(),
-		),
-	)
+// send sends the given message to the given recipients in a single transaction,
+// like smtp.SendMail, but going on if only some of the recipients are rejected: in
+// that case, a RejectedRecipientsError is returned after sending. If all of them
+// are rejected, the error is not a RejectedRecipientsError, as nothing is sent.
+func (d Destination) send(from string, to []string, msg []byte) error {
+	c, err := smtp.Dial(d.Host + ":" + d.Port)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer func() { _ = c.Close() }()
 
-	var buf bytes.Buffer
-	if err := md.Convert([]byte(r.Description), &buf); err != nil {
-		return plaintextContent(r)
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: d.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if auth := d.auth(); auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
 	}
 
-	return fmt.Sprintf(`<h1>New release for %s/%s: %s
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("mail from: %w", err)
+	}
+	rejected := make(map[string]error)
+	for _, address := range to {
+		if err := c.Rcpt(address); err != nil {
+			rejected[address] = err
+		}
+	}
+	if len(rejected) == len(to) {
+		return fmt.Errorf("rcpt: all %s", &RejectedRecipientsError{Rejected: rejected})
+	}
 
-</hr>
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	if err := c.Quit(); err != nil {
+		return fmt.Errorf("quit: %w", err)
+	}
 
-%s
+	if len(rejected) > 0 {
+		return &RejectedRecipientsError{Rejected: rejected}
+	}
+	return nil
+}
 
-</hr>
+// auth returns smtp.Auth if username and password are set, otherwise nil indicating NOAUTH
+func (d Destination) auth() smtp.Auth {
+	if d.Username == "" && d.Password == "" {
+		return nil
+	}
+	return smtp.PlainAuth("", d.From, d.Password, d.Host)
+}
 
-URL: <a href="%s">%s</a>`, r.Author, r.Project, r.Version,
-		buf.String(),
-		r.URL, r.URL)
+// priorityHeaders returns the headers that mark the email as important, according
+// to the priority.
+func priorityHeaders(p release.Priority) string {
+	switch p {
+	case release.PriorityUrgent:
+		return "X-Priority: 1\r\nImportance: high\r\n"
+	case release.PriorityHigh:
+		return "X-Priority: 2\r\nImportance: high\r\n"
+	default:
+		return ""
+	}
 }
@@ -1,12 +1,19 @@
 package smtp
 
 import (
-	"fmt"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
 	"strconv"
 	"strings"
 	"testing"
 
 	smtpmock "github.com/mocktools/go-smtp-mock/v2"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations/templates"
 	"it.davquar/gitrelnoty/pkg/release"
 )
 
@@ -24,20 +31,26 @@ func TestNotifySMTP(t *testing.T) {
 
 	d := Destination{
 		From:     "from@test.test",
-		To:       "to@test.test",
+		To:       destinations.Addresses{"to@test.test"},
 		Host:     "127.0.0.1",
 		Username: "",
 		Password: "",
 		Port:     strconv.Itoa(server.PortNumber()),
 	}
 
-	err = d.Notify(release.Release{
+	if err := d.Notify(destinations.Notification{}); err == nil {
+		t.Fatal("expected error notifying before Init")
+	}
+	if err := d.Init(); err != nil {
+		t.Fatalf("unexpected error initializing destination: %v", err)
+	}
+	err = d.Notify(destinations.Notification{Release: release.Release{
 		Project:     "dummy-project",
 		Author:      "dummy-author",
 		Version:     "v1.2.3",
 		Description: "This is a dummy release just for testing.",
 		URL:         "some-url",
-	})
+	}})
 	if err != nil {
 		t.Fatalf("unexpected error sending email: %v", err)
 	}
@@ -51,27 +64,293 @@ func TestNotifySMTP(t *testing.T) {
 		t.Fatalf("expected 1 receiver, got %d", len(msgs[0].RcpttoRequestResponse()))
 	}
 
-	expmsg := makeRawMsg(d.From, d.To)
-	if msgs[0].MsgRequest() != expmsg {
-		t.Fatalf("expected msg '%s', got '%s'", expmsg, msgs[0].MsgRequest())
+	header, parts := parseEmail(t, msgs[0].MsgRequest())
+	for name, expected := range map[string]string{
+		"From":                      "<from@test.test>",
+		"To":                        "<to@test.test>",
+		"Subject":                   "New release: dummy-author/dummy-project v1.2.3",
+		"Mime-Version":              "1.0",
+		"Content-Transfer-Encoding": "quoted-printable",
+	} {
+		if got := header.Get(name); got != expected {
+			t.Errorf("expected %s '%s', got '%s'", name, expected, got)
+		}
+	}
+	if _, err := header.Date(); err != nil {
+		t.Errorf("invalid date: %v", err)
+	}
+	if id := header.Get("Message-Id"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@test.test>") {
+		t.Errorf("invalid message id '%s'", id)
 	}
-}
-
-func makeRawMsg(from string, to string) string {
-	s := fmt.Sprintf(`From: %s
-To: %s
-Subject: New release: dummy-author/dummy-project v1.2.3
 
-GHRelNoty
+	expected := `GHRelNoty
 ---------
 
 New release for dummy-author/dummy-project: v1.2.3
 
 This is a dummy release just for testing.
 
-URL: some-url
-`, from, to)
+URL: some-url`
+	if len(parts) != 1 || parts["text/plain"] != expected {
+		t.Fatalf("expected text body '%s', got %v", expected, parts)
+	}
+}
+
+func TestNotifyMultipart(t *testing.T) {
+	server := smtpmock.New(smtpmock.ConfigurationAttr{})
+	if err := server.Start(); err != nil {
+		t.Fatalf("cannot start smtp mock server: %v", err)
+	}
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("smtp mock server error while closing: %v", err)
+		}
+	}()
+
+	d := Destination{
+		From: "GHRelNoty <from@test.test>",
+		To:   destinations.Addresses{"Dèstinatàrio <to@test.test>"},
+		Host: "127.0.0.1",
+		Port: strconv.Itoa(server.PortNumber()),
+		HTML: true,
+	}
+
+	description := "Sécurité fix with a very long line that has to be wrapped by the quoted-printable encoding, because lines can't be longer than 76 characters."
+	if err := d.Init(); err != nil {
+		t.Fatalf("unexpected error initializing destination: %v", err)
+	}
+	err := d.Notify(destinations.Notification{Release: release.Release{
+		Project:     "dummy-project",
+		Author:      "dummy-author",
+		Version:     "v1.2.3",
+		Description: description,
+		URL:         "https://example.com/?a=1&b=2",
+		Labels:      []string{"sécurité"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error sending email: %v", err)
+	}
+
+	msgs := server.MessagesAndPurge()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	raw := msgs[0].MsgRequest()
+	if got := msgs[0].MailfromRequest(); got != "MAIL FROM:<from@test.test>" {
+		t.Errorf("unexpected envelope sender '%s'", got)
+	}
+	for _, line := range strings.Split(raw, "\r\n") {
+		if len(line) > 78 {
+			t.Errorf("line longer than 78 characters: '%s'", line)
+		}
+		for _, c := range line {
+			if c > 127 {
+				t.Fatalf("non-ASCII character in line '%s'", line)
+			}
+		}
+	}
+
+	header, parts := parseEmail(t, raw)
+	if got := header.Get("Subject"); !strings.HasPrefix(got, "=?utf-8?q?") {
+		t.Errorf("expected encoded subject, got '%s'", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if err != nil || subject != "[sécurité] New release: dummy-author/dummy-project v1.2.3" {
+		t.Errorf("unexpected subject '%s', %v", subject, err)
+	}
+	if to, err := header.AddressList("To"); err != nil || to[0].Name != "Dèstinatàrio" {
+		t.Errorf("unexpected to %v, %v", to, err)
+	}
+
+	if len(parts) != 2 {
+		t.Fatalf("expected text and html parts, got %v", parts)
+	}
+	if !strings.Contains(parts["text/plain"], description) {
+		t.Errorf("expected text part to contain the description, got '%s'", parts["text/plain"])
+	}
+	for _, expected := range []string{"<p>" + description + "</p>", `<a href="https://example.com/?a=1&amp;b=2">`} {
+		if !strings.Contains(parts["text/html"], expected) {
+			t.Errorf("expected html part to contain '%s', got '%s'", expected, parts["text/html"])
+		}
+	}
+}
+
+// parseEmail returns the header of the given raw email, and its decoded parts by
+// media type, with LF line endings.
+func parseEmail(t *testing.T, raw string) (mail.Header, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("cannot parse email: %v", err)
+	}
+
+	parts := make(map[string]string)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid content type: %v", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		parts[mediaType] = readQuotedPrintable(t, msg.Body)
+		return msg.Header, parts
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("cannot read part: %v", err)
+		}
+		if p.Header.Get("Content-Transfer-Encoding") != "quoted-printable" {
+			t.Fatalf("expected quoted-printable part, got %v", p.Header)
+		}
+		partType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[partType] = readQuotedPrintable(t, p)
+	}
+	return msg.Header, parts
+}
+
+func readQuotedPrintable(t *testing.T, r io.Reader) string {
+	t.Helper()
+	body, err := io.ReadAll(quotedprintable.NewReader(r))
+	if err != nil {
+		t.Fatalf("cannot decode body: %v", err)
+	}
+	return strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+}
+
+func TestPlaintextAssets(t *testing.T) {
+	body := plaintextContent(t, release.Release{
+		Project: "dummy-project",
+		Author:  "dummy-author",
+		Version: "v1.2.3",
+		URL:     "some-url",
+		Assets: []release.Asset{{
+			Name:        "dummy_linux_amd64.tar.gz",
+			Size:        1024,
+			ContentType: "application/gzip",
+			URL:         "some-asset-url",
+			Digest:      "sha256:abcd",
+		}},
+	})
+
+	expected := "Assets:\n\n- dummy_linux_amd64.tar.gz (application/gzip, 1024 bytes): some-asset-url\n  sha256:abcd"
+	if !strings.HasSuffix(body, expected) {
+		t.Fatalf("expected body to end with '%s', got '%s'", expected, body)
+	}
+}
+
+func TestPlaintextReferences(t *testing.T) {
+	body := plaintextContent(t, release.Release{
+		URL: "some-url",
+		References: []release.Reference{
+			{Path: "infra/network/.terraform.lock.hcl", Version: "5.31.0", Outdated: true, Behind: 3},
+			{Path: "infra/dns/.terraform.lock.hcl", Version: "5.40.0"},
+		},
+	})
+
+	expected := "URL: some-url\n\nIn use:\n\n- 5.31.0 in infra/network/.terraform.lock.hcl (outdated, 3 releases behind)\n- 5.40.0 in infra/dns/.terraform.lock.hcl"
+	if !strings.HasSuffix(body, expected) {
+		t.Fatalf("expected body to end with '%s', got '%s'", expected, body)
+	}
+}
+
+func TestPlaintextBehind(t *testing.T) {
+	body := plaintextContent(t, release.Release{
+		Project: "dummy-project",
+		Author:  "dummy-author",
+		Version: "v2.1.0",
+		Behind:  &release.Behind{Current: "v1.4.1", Major: 2, Minor: 1, Patch: 1},
+	})
+
+	expected := "New release for dummy-author/dummy-project: v2.1.0\n\nDeployed: v1.4.1, 4 releases behind (2 major, 1 minor, 1 patch)\n"
+	if !strings.Contains(body, expected) {
+		t.Fatalf("expected body to contain '%s', got '%s'", expected, body)
+	}
+}
+
+func TestSubjectPrefix(t *testing.T) {
+	set, err := templates.Parse(templates.Config{}, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error parsing templates: %v", err)
+	}
+
+	r := release.Release{Project: "p", Author: "a", Version: "1.0.0", Priority: release.PriorityUrgent, Labels: []string{"security", "breaking"}}
+	if got, err := set.Subject(templates.Data{Release: r}); err != nil || got != "[URGENT] [security, breaking] New release: a/p 1.0.0" {
+		t.Fatalf("unexpected subject '%s', %v", got, err)
+	}
+	if got := priorityHeaders(r.Priority); got != "X-Priority: 1\r\nImportance: high\r\n" {
+		t.Fatalf("unexpected headers '%s'", got)
+	}
+
+	if got, err := set.Subject(templates.Data{Release: release.Release{Project: "p", Author: "a", Version: "1.0.0"}}); err != nil || got != "New release: a/p 1.0.0" {
+		t.Fatalf("expected no prefix, got '%s', %v", got, err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	server := smtpmock.New(smtpmock.ConfigurationAttr{})
+	if err := server.Start(); err != nil {
+		t.Fatalf("cannot start smtp mock server: %v", err)
+	}
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("smtp mock server error while closing: %v", err)
+		}
+	}()
+
+	d := Destination{
+		From: "from@test.test",
+		To:   destinations.Addresses{"to@test.test"},
+		Host: "127.0.0.1",
+		Port: strconv.Itoa(server.PortNumber()),
+		HTML: true,
+		Config: templates.Config{
+			Subject: "{{ .Repo }} {{ (semver .Version).Major }}.x: {{ .Version }}",
+			HTML:    "<h1>{{ .Repository.Name }}</h1>{{ .Description | truncate 15 | markdown }}",
+		},
+	}
+	if err := d.Init(); err != nil {
+		t.Fatalf("unexpected error parsing templates: %v", err)
+	}
+
+	err := d.Notify(destinations.Notification{
+		Release: release.Release{
+			Project:     "dummy-project",
+			Author:      "dummy-author",
+			Version:     "v1.2.3",
+			Description: "Some **bold** notes",
+		},
+		Repository: destinations.Repository{Name: "<dummy>"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error sending email: %v", err)
+	}
+
+	msgs := server.MessagesAndPurge()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	header, parts := parseEmail(t, msgs[0].MsgRequest())
+	if got := header.Get("Subject"); got != "dummy-author/dummy-project 1.x: v1.2.3" {
+		t.Errorf("unexpected subject '%s'", got)
+	}
+	if expected := "<h1>&lt;dummy&gt;</h1><p>Some <strong>bold</strong> …</p>"; parts["text/html"] != expected {
+		t.Errorf("expected html part '%s', got '%s'", expected, parts["text/html"])
+	}
+}
 
-	// set \r\n as newline sequence, because that's what is used in the msg field
-	return strings.ReplaceAll(s, "\n", "\r\n")
+func plaintextContent(t *testing.T, r release.Release) string {
+	t.Helper()
+	set, err := templates.Parse(templates.Config{}, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error parsing templates: %v", err)
+	}
+	body, err := set.Text(templates.Data{Release: r})
+	if err != nil {
+		t.Fatalf("unexpected error rendering: %v", err)
+	}
+	return body
 }
@@ -0,0 +1,205 @@
+// Package templates renders the notifications of destinations with text/template,
+// or html/template for HTML bodies, over the Data of a notification.
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	goldmarkext "github.com/yuin/goldmark/extension"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// Config holds the templates of a destination, each given inline or as the path
+// of a file. The ones not set fall back to the defaults of the destination.
+type Config struct {
+	Subject     string `yaml:"subject_template"`
+	SubjectFile string `yaml:"subject_template_file"`
+	Text        string `yaml:"text_template"`
+	TextFile    string `yaml:"text_template_file"`
+	HTML        string `yaml:"html_template"`
+	HTMLFile    string `yaml:"html_template_file"`
+}
+
+// Defaults are the built-in templates of a destination. Common holds definitions
+// parsed along with each template, including the configured ones.
+type Defaults struct {
+	Common  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Data is what templates are rendered over: the fields of the release at the top
+// level, like .Version, and the repository it's notified for in .Repository.
+type Data struct {
+	release.Release
+	Repository destinations.Repository
+}
+
+// NewData returns the Data to render the templates of the given notification with.
+func NewData(n destinations.Notification) Data {
+	return Data{Release: n.Release, Repository: n.Repository}
+}
+
+// Set holds the parsed templates of a destination.
+type Set struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Parse returns the Set of the templates in Config, using Defaults for the ones not set.
+func Parse(c Config, d Defaults) (*Set, error) {
+	subject, err := source(c.Subject, c.SubjectFile, d.Subject)
+	if err != nil {
+		return nil, err
+	}
+	text, err := source(c.Text, c.TextFile, d.Text)
+	if err != nil {
+		return nil, err
+	}
+	html, err := source(c.HTML, c.HTMLFile, d.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Set
+	if s.subject, err = parseText("subject", d.Common, subject); err != nil {
+		return nil, err
+	}
+	if s.text, err = parseText("text", d.Common, text); err != nil {
+		return nil, err
+	}
+
+	s.html, err = htmltemplate.New("html").Funcs(Funcs()).Parse(d.Common)
+	if err == nil {
+		s.html, err = s.html.Parse(html)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse html template: %w", err)
+	}
+	return &s, nil
+}
+
+// source returns the inline template, or the content of its file, or the default one.
+func source(inline string, file string, fallback string) (string, error) {
+	switch {
+	case inline != "" && file != "":
+		return "", fmt.Errorf("template and template file can't be both set")
+	case inline != "":
+		return inline, nil
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("read template: %w", err)
+		}
+		return string(content), nil
+	}
+	return fallback, nil
+}
+
+func parseText(name string, common string, source string) (*texttemplate.Template, error) {
+	t, err := texttemplate.New(name).Funcs(Funcs()).Parse(common)
+	if err == nil {
+		t, err = t.Parse(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// Subject renders the subject template, joining its lines with spaces.
+func (s *Set) Subject(data any) (string, error) {
+	var buf bytes.Buffer
+	if err := s.subject.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render subject: %w", err)
+	}
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
+
+// Text renders the plain text body template.
+func (s *Set) Text(data any) (string, error) {
+	var buf bytes.Buffer
+	if err := s.text.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render text: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// HTML renders the HTML body template.
+func (s *Set) HTML(data any) (string, error) {
+	var buf bytes.Buffer
+	if err := s.html.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render html: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Funcs returns the helpers available to templates:
+//   - markdown renders markdown, like release notes, as HTML.
+//   - truncate shortens a string to the given number of characters, adding an ellipsis.
+//   - date formats a time with the given layout, like 2006-01-02, or returns an
+//     empty string for the zero time.
+//   - now returns the current time.
+//   - semver parses a version in its Major, Minor, Patch, Prerelease and Build parts,
+//     which are zero if it's not a semantic version.
+//   - join joins strings with the given separator.
+func Funcs() map[string]any {
+	return map[string]any{
+		"markdown": Markdown,
+		"truncate": truncate,
+		"date":     date,
+		"now":      time.Now,
+		"semver":   parseSemver,
+		"join":     join,
+	}
+}
+
+var md = goldmark.New(goldmark.WithExtensions(goldmarkext.GFM))
+
+// Markdown renders the given markdown as HTML, which is not escaped further by
+// html/template. Raw HTML and links with dangerous schemes, like javascript:, are
+// omitted, as release notes come from third parties. It falls back to the escaped
+// markdown source.
+func Markdown(s string) htmltemplate.HTML {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(s), &buf); err != nil {
+		return htmltemplate.HTML("<pre>" + htmltemplate.HTMLEscapeString(s) + "</pre>") // #nosec G203 -- the source is escaped
+	}
+	return htmltemplate.HTML(buf.String()) // #nosec G203 -- goldmark omits raw HTML and dangerous links
+}
+
+func truncate(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
+
+func date(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func parseSemver(s string) semver.Version {
+	v, _ := semver.Parse(s)
+	return v
+}
+
+func join(sep string, elems []string) string {
+	return strings.Join(elems, sep)
+}
@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "subject.tmpl")
+	if err := os.WriteFile(file, []byte("From file: {{ template \"name\" . }}"), 0600); err != nil {
+		t.Fatalf("cannot write template: %v", err)
+	}
+
+	defaults := Defaults{
+		Common:  `{{ define "name" }}{{ .Name }}{{ end }}`,
+		Subject: "Default",
+		Text:    "Text for {{ template \"name\" . }}",
+		HTML:    "<p>{{ .Name }}</p>",
+	}
+	set, err := Parse(Config{SubjectFile: file}, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := struct{ Name string }{"<name>"}
+	for _, c := range []struct {
+		render func(any) (string, error)
+		want   string
+	}{
+		{set.Subject, "From file: <name>"},
+		{set.Text, "Text for <name>"},
+		{set.HTML, "<p>&lt;name&gt;</p>"},
+	} {
+		if got, err := c.render(data); err != nil || got != c.want {
+			t.Errorf("expected '%s', got '%s', %v", c.want, got, err)
+		}
+	}
+
+	if _, err := Parse(Config{Subject: "inline", SubjectFile: file}, defaults); err == nil {
+		t.Error("expected error with both inline and file template")
+	}
+	if _, err := Parse(Config{Text: "{{ .Name"}, defaults); err == nil {
+		t.Error("expected error with invalid template")
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	data := map[string]any{
+		"Version":     "v2.4.1-rc.1",
+		"PublishedAt": time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		"Notes":       "Fixes a bug in the parser",
+		"Labels":      []string{"security", "breaking"},
+	}
+	cases := map[string]string{
+		`{{ (semver .Version).Major }}.{{ (semver .Version).Minor }} {{ (semver .Version).Prerelease }}`: "2.4 rc.1",
+		`{{ (semver "main").Major }}`:                    "0",
+		`{{ .PublishedAt | date "2006-01-02" }}`:         "2025-03-01",
+		`{{ .Notes | truncate 10 }}`:                     "Fixes a b…",
+		`{{ .Notes | truncate 100 }}`:                    "Fixes a bug in the parser",
+		`{{ markdown "*new*" }}`:                         "<p><em>new</em></p>\n",
+		`{{ markdown "<script>alert(1)</script>" }}`:     "<!-- raw HTML omitted -->\n",
+		`{{ markdown "[x](javascript:alert(1))" }}`:      "<p><a href=\"\">x</a></p>\n",
+		`{{ .Labels | join ", " }}`:                      "security, breaking",
+		`{{ if (now).After .PublishedAt }}past{{ end }}`: "past",
+	}
+
+	for source, want := range cases {
+		set, err := Parse(Config{Text: source}, Defaults{})
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %v", source, err)
+		}
+		if got, err := set.Text(data); err != nil || got != want {
+			t.Errorf("%s: expected '%s', got '%s', %v", source, want, got, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package ghrelnoty
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// ReleaseFilters narrow down the releases to notify, with regexes matched against
+// the tag, the name and the normalized version of each release: a release is kept
+// if it matches at least one of Include, when set, and none of Exclude.
+type ReleaseFilters struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// Validate returns an error if the ReleaseFilters can't be used, and compiles their
+// patterns otherwise. It must be called before Allows.
+func (f *ReleaseFilters) Validate() error {
+	var err error
+	if f.include, err = compileFilters(f.Include); err != nil {
+		return err
+	}
+	f.exclude, err = compileFilters(f.Exclude)
+	return err
+}
+
+func compileFilters(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid release filter %s: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Allows returns true if the release passes the filters.
+func (f ReleaseFilters) Allows(r release.Release) bool {
+	if len(f.include) > 0 && !slices.ContainsFunc(f.include, matcher(r)) {
+		return false
+	}
+	return !slices.ContainsFunc(f.exclude, matcher(r))
+}
+
+// matcher returns a function reporting whether a regex matches the tag, the name
+// or the version of the release.
+func matcher(r release.Release) func(*regexp.Regexp) bool {
+	return func(re *regexp.Regexp) bool {
+		return re.MatchString(r.Tag) || re.MatchString(r.Name) || re.MatchString(r.Version)
+	}
+}
+
+// suppress returns the releases that pass the global filters, the ones of the group
+// of the repository and its own, and the tags of the others. Releases suppressed at
+// a previous check stay suppressed even if the filters change, so that they don't
+// resurface.
+func (s Service) suppress(repo RepositoryConfig, releases []release.Release, current store.Record) ([]release.Release, []string) {
+	var (
+		kept       []release.Release
+		suppressed []string
+		group      = s.Config.Groups[repo.Group]
+	)
+	for _, r := range releases {
+		seen := slices.Contains(current.Suppressed, r.Tag)
+		if !seen && s.Config.Releases.Allows(r) && group.Releases.Allows(r) && repo.Releases.Allows(r) {
+			kept = append(kept, r)
+			continue
+		}
+
+		if !seen {
+			metrics.ReleaseSuppressed()
+		}
+		suppressed = append(suppressed, r.Tag)
+	}
+	return kept, suppressed
+}
@@ -0,0 +1,167 @@
+package ghrelnoty
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestReleaseFiltersAllows(t *testing.T) {
+	filters := ReleaseFilters{
+		Include: []string{`^v1\.`, `LTS`},
+		Exclude: []string{`-nightly$`},
+	}
+	if err := filters.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		release  release.Release
+		expected bool
+	}{
+		{release.Release{Tag: "v1.2.0", Version: "1.2.0"}, true},
+		{release.Release{Tag: "v2.0.0", Version: "2.0.0"}, false},
+		{release.Release{Tag: "v2.0.1", Name: "2.0.1 LTS", Version: "2.0.1"}, true},
+		{release.Release{Tag: "v1.3.0-nightly", Version: "1.3.0-nightly"}, false},
+		{release.Release{Tag: "release-1.4", Name: "v1.4.0", Version: "1.4.0"}, true},
+	}
+
+	for _, c := range cases {
+		if got := filters.Allows(c.release); got != c.expected {
+			t.Errorf("%s: expected %t, got %t", c.release.Tag, c.expected, got)
+		}
+	}
+
+	if !(ReleaseFilters{}).Allows(release.Release{Tag: "anything"}) {
+		t.Error("expected empty filters to allow everything")
+	}
+	if err := (&ReleaseFilters{Exclude: []string{"("}}).Validate(); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestProcessSuppressesReleases(t *testing.T) {
+	s, err := New(Config{
+		DBPath:   filepath.Join(t.TempDir(), "ghrelnoty.db"),
+		Releases: ReleaseFilters{Exclude: []string{`-rc`}},
+	})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	defer s.Close()
+
+	notifications := make(chanNotifier, 3)
+	s.Notifiers = map[string]Notifier{"chan": notifications}
+
+	repo := RepositoryConfig{
+		Name:        "author/name",
+		Destination: "chan",
+		Releases:    ReleaseFilters{Exclude: []string{`^nightly$`}},
+	}
+	if err := repo.Releases.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.process(context.Background(), repo, []release.Release{{Version: "1.0.0", Tag: "v1.0.0"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-notifications
+
+	releases := []release.Release{
+		{Version: "nightly", Tag: "nightly"},
+		{Version: "1.1.0-rc1", Tag: "v1.1.0-rc1"},
+		{Version: "1.0.0", Tag: "v1.0.0"},
+	}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 0 {
+		t.Fatalf("expected no notifications, got %d", len(notifications))
+	}
+
+	record, err := s.Store.Get("author/name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Version != "1.0.0" || !slices.Equal(record.Suppressed, []string{"nightly", "v1.1.0-rc1"}) {
+		t.Fatalf("unexpected record %+v", record)
+	}
+
+	// Suppressed releases don't resurface when the filters change.
+	s.Config.Releases = ReleaseFilters{}
+	releases = append([]release.Release{{Version: "1.1.0", Tag: "v1.1.0"}}, releases...)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if r := <-notifications; r.Tag != "v1.1.0" {
+		t.Fatalf("expected v1.1.0 to be notified, got %s", r.Tag)
+	}
+}
+
+func TestProcessGroupFilters(t *testing.T) {
+	s, err := New(Config{
+		DBPath:   filepath.Join(t.TempDir(), "ghrelnoty.db"),
+		Releases: ReleaseFilters{Exclude: []string{`-rc`}},
+		Groups: map[string]GroupConfig{
+			"infra": {Releases: ReleaseFilters{Exclude: []string{`^v1\.1\.`}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	defer s.Close()
+
+	notifications := make(chanNotifier, 3)
+	s.Notifiers = map[string]Notifier{"chan": notifications}
+
+	r, err := s.newReleaser(RepositoryConfig{
+		Type:        "github",
+		Name:        "author/name",
+		Destination: "chan",
+		Group:       "infra",
+		Releases:    ReleaseFilters{Exclude: []string{`^nightly$`}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := r.Config()
+
+	if err := s.process(context.Background(), repo, []release.Release{{Version: "1.0.0", Tag: "v1.0.0"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-notifications
+
+	releases := []release.Release{
+		{Version: "1.2.0", Tag: "v1.2.0"},
+		{Version: "1.1.1", Tag: "v1.1.1"},
+		{Version: "nightly", Tag: "nightly"},
+		{Version: "1.1.0-rc1", Tag: "v1.1.0-rc1"},
+		{Version: "1.0.0", Tag: "v1.0.0"},
+	}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if r := <-notifications; r.Tag != "v1.2.0" {
+		t.Fatalf("expected v1.2.0 to be notified, got %s", r.Tag)
+	}
+
+	record, err := s.Store.Get("author/name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(record.Suppressed, []string{"v1.1.1", "nightly", "v1.1.0-rc1"}) {
+		t.Fatalf("expected the global, group and repository filters to apply, got %+v", record)
+	}
+
+	if _, err := s.newReleaser(RepositoryConfig{Type: "github", Name: "author/other", Group: "unknown"}); err == nil {
+		t.Fatal("expected error for an unknown group")
+	}
+}
@@ -0,0 +1,69 @@
+package ghrelnoty
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/providers/gomod"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// GoModProvider lists the direct requirements of go.mod files.
+type GoModProvider struct {
+	ProviderConfig
+}
+
+func (p GoModProvider) Config() ProviderConfig {
+	return p.ProviderConfig
+}
+
+var majorSuffixRegex = regexp.MustCompile(`^v\d+$`)
+
+// Repositories returns the modules required by the go.mod files matching the
+// glob patterns in ProviderConfig.Paths, with the files and versions requiring them.
+// Modules are watched through the Go module proxy, as most of them publish tags
+// without GitHub releases; if ProviderConfig.Source is github, the ones hosted on
+// GitHub are watched through their repository releases instead.
+// The files are read again at every check, so that changes are picked up.
+func (p GoModProvider) Repositories(_ context.Context) ([]RepositoryConfig, error) {
+	requirements, err := gomod.Find(p.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []RepositoryConfig
+	byName := make(map[string]int)
+	for _, req := range requirements {
+		repo := p.module(req.Module)
+
+		i, ok := byName[repo.Name]
+		if !ok {
+			i = len(repos)
+			byName[repo.Name] = i
+			repos = append(repos, repo)
+		}
+		repos[i].References = append(repos[i].References, release.Reference{
+			Path:    req.Path,
+			Version: req.Version,
+		})
+	}
+
+	return repos, nil
+}
+
+// module returns the RepositoryConfig to watch the given module.
+func (p GoModProvider) module(path string) RepositoryConfig {
+	parts := strings.Split(path, "/")
+	if p.Source == "github" && len(parts) >= 3 && parts[0] == "github.com" {
+		// Modules in subdirectories are tagged with a prefix, so they go through the proxy.
+		if len(parts) == 3 || majorSuffixRegex.MatchString(parts[3]) {
+			return p.repository(parts[1] + "/" + parts[2])
+		}
+	}
+
+	repo := p.repository(path)
+	repo.Type = "goproxy"
+	repo.Registry = p.Registry
+	return repo
+}
@@ -0,0 +1,74 @@
+package ghrelnoty
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGoModProvider(t *testing.T) {
+	p := GoModProvider{
+		ProviderConfig: ProviderConfig{
+			Paths:       []string{"providers/gomod/testdata/services/*/go.mod"},
+			Destination: "email",
+		},
+	}
+
+	repos, err := p.Repositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		name    string
+		typ     string
+		usedBy  int
+		version string
+	}{
+		{"go.etcd.io/bbolt", "goproxy", 2, "v1.3.10"},
+		{"github.com/google/go-github/v68", "goproxy", 1, "v68.0.0"},
+		{"github.com/yuin/goldmark", "goproxy", 1, "v1.7.13"},
+		{"gopkg.in/yaml.v3", "goproxy", 1, "v3.0.1"},
+	}
+	if len(repos) != len(expected) {
+		t.Fatalf("expected %d repositories, got %d: %+v", len(expected), len(repos), repos)
+	}
+
+	for i, e := range expected {
+		r := repos[i]
+		if r.Name != e.name || r.Type != e.typ || r.Destination != "email" {
+			t.Errorf("unexpected repository %+v, expected %s of type %s", r, e.name, e.typ)
+		}
+		if len(r.References) != e.usedBy || r.References[0].Version != e.version {
+			t.Errorf("unexpected references for %s: %+v", e.name, r.References)
+		}
+	}
+}
+
+func TestGoModProviderProxySource(t *testing.T) {
+	p := GoModProvider{
+		ProviderConfig: ProviderConfig{
+			Registry: "https://goproxy.example.com",
+		},
+	}
+
+	repo := p.module("github.com/google/go-github/v68")
+	if repo.Type != "goproxy" || repo.Name != "github.com/google/go-github/v68" || repo.Registry != "https://goproxy.example.com" {
+		t.Fatalf("unexpected repository %+v", repo)
+	}
+}
+
+func TestGoModProviderGitHubSource(t *testing.T) {
+	p := GoModProvider{ProviderConfig: ProviderConfig{Source: "github"}}
+
+	cases := map[string][2]string{
+		"github.com/google/go-github/v68":  {"github", "google/go-github"},
+		"github.com/yuin/goldmark":         {"github", "yuin/goldmark"},
+		"github.com/author/repo/submodule": {"goproxy", "github.com/author/repo/submodule"},
+		"go.etcd.io/bbolt":                 {"goproxy", "go.etcd.io/bbolt"},
+	}
+	for path, expected := range cases {
+		if repo := p.module(path); repo.Type != expected[0] || repo.Name != expected[1] {
+			t.Errorf("%s: expected %s of type %s, got %+v", path, expected[1], expected[0], repo)
+		}
+	}
+}
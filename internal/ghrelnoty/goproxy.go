@@ -0,0 +1,154 @@
+package ghrelnoty
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// defaultGoProxy is the Go module proxy used when RepositoryConfig.Registry is not set.
+const defaultGoProxy = "https://proxy.golang.org"
+
+// httpClient is used for the requests to registries other than GitHub.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// GoProxyRepository gets the versions of a Go module, whose path is
+// RepositoryConfig.Name, from a Go module proxy.
+type GoProxyRepository struct {
+	RepositoryConfig
+	Client *http.Client
+}
+
+func (r GoProxyRepository) Config() RepositoryConfig {
+	return r.RepositoryConfig
+}
+
+// GetReleases gets the semver versions of the module, newest first. Prereleases are
+// filtered according to RepositoryConfig.Prereleases, and only the latest version
+// has its publication time. There are no rate limits to report.
+func (r GoProxyRepository) GetReleases(ctx context.Context) ([]release.Release, RateLimitData, error) {
+	body, err := r.get(ctx, "@v/list")
+	if err != nil {
+		return nil, RateLimitData{}, err
+	}
+
+	var versions []semver.Version
+	raw := make(map[semver.Version]string)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		v, err := semver.Parse(scanner.Text())
+		if err != nil || !r.WantsRelease(v.Prerelease != "") {
+			continue
+		}
+		versions = append(versions, v)
+		raw[v] = strings.TrimSpace(scanner.Text())
+	}
+	slices.SortFunc(versions, func(a, b semver.Version) int {
+		return b.Compare(a)
+	})
+	if len(versions) > releasesPerPage {
+		versions = versions[:releasesPerPage]
+	}
+
+	author, project := r.separateModule()
+	releases := make([]release.Release, 0, len(versions))
+	for _, v := range versions {
+		version, err := r.Extract(raw[v], "")
+		if err != nil {
+			return nil, RateLimitData{}, fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		releases = append(releases, release.Release{
+			Kind:       release.KindNew,
+			Project:    project,
+			Author:     author,
+			Version:    version,
+			Tag:        raw[v],
+			URL:        fmt.Sprintf("https://pkg.go.dev/%s@%s", r.Name, raw[v]),
+			Prerelease: v.Prerelease != "",
+		})
+	}
+
+	if len(releases) > 0 {
+		info, err := r.get(ctx, "@v/"+releases[0].Tag+".info")
+		if err != nil {
+			return nil, RateLimitData{}, err
+		}
+
+		var versionInfo struct {
+			Time time.Time `json:"Time"`
+		}
+		if err := json.Unmarshal([]byte(info), &versionInfo); err != nil {
+			return nil, RateLimitData{}, fmt.Errorf("%s: decode info: %w", r.Name, err)
+		}
+		releases[0].PublishedAt = versionInfo.Time
+	}
+
+	return releases, RateLimitData{}, nil
+}
+
+// get returns the body of the given endpoint of the proxy, for the module.
+func (r GoProxyRepository) get(ctx context.Context, endpoint string) (string, error) {
+	proxy := r.Registry
+	if proxy == "" {
+		proxy = defaultGoProxy
+	}
+	url := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(proxy, "/"), escapeModulePath(r.Name), endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", r.Name, err)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", r.Name, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: GET %s: %s", r.Name, url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%s: read body: %w", r.Name, err)
+	}
+	return string(body), nil
+}
+
+// separateModule returns the module path up to its last element, and the last element.
+func (r GoProxyRepository) separateModule() (string, string) {
+	i := strings.LastIndex(r.Name, "/")
+	if i < 0 {
+		return "", r.Name
+	}
+	return r.Name[:i], r.Name[i+1:]
+}
+
+// escapeModulePath escapes upper case letters as required by the Go module proxy
+// protocol: each one is replaced by an exclamation mark and its lower case.
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, c := range path {
+		if unicode.IsUpper(c) {
+			b.WriteRune('!')
+			b.WriteRune(unicode.ToLower(c))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
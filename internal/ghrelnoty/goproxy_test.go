@@ -0,0 +1,59 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGoProxyRepository(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!burnt!sushi/toml/@v/list":
+			fmt.Fprint(w, "v1.2.0\nv1.10.0\nv1.9.1\nv1.11.0-rc.1\n")
+		case "/github.com/!burnt!sushi/toml/@v/v1.10.0.info":
+			fmt.Fprint(w, `{"Version":"v1.10.0","Time":"2025-03-01T10:00:00Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer proxy.Close()
+
+	repo := GoProxyRepository{
+		RepositoryConfig: RepositoryConfig{
+			Type:     "goproxy",
+			Name:     "github.com/BurntSushi/toml",
+			Registry: proxy.URL,
+		},
+		Client: proxy.Client(),
+	}
+
+	releases, _, err := repo.GetReleases(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(releases) != 3 {
+		t.Fatalf("expected 3 releases, got %d: %+v", len(releases), releases)
+	}
+	for i, version := range []string{"v1.10.0", "v1.9.1", "v1.2.0"} {
+		if releases[i].Version != version {
+			t.Errorf("expected %s at %d, got %s", version, i, releases[i].Version)
+		}
+	}
+
+	latest := releases[0]
+	if latest.Author != "github.com/BurntSushi" || latest.Project != "toml" ||
+		latest.URL != "https://pkg.go.dev/github.com/BurntSushi/toml@v1.10.0" ||
+		latest.PublishedAt.Year() != 2025 {
+		t.Fatalf("unexpected release %+v", latest)
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	if got := escapeModulePath("github.com/Azure/azure-sdk-for-go"); got != "github.com/!azure/azure-sdk-for-go" {
+		t.Fatalf("unexpected escaped path %s", got)
+	}
+}
@@ -0,0 +1,109 @@
+package ghrelnoty
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/providers/images"
+	"it.davquar/gitrelnoty/internal/oci"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// ImagesProvider lists the container images used by docker-compose files and
+// Kubernetes/Kustomize manifests.
+type ImagesProvider struct {
+	ProviderConfig
+	Client *oci.Client
+
+	// sources caches the GitHub repository, or an empty string, by image reference.
+	sources *sync.Map
+}
+
+func (p ImagesProvider) Config() ProviderConfig {
+	return p.ProviderConfig
+}
+
+// Repositories returns the images referenced in the YAML files under ProviderConfig.Paths,
+// with the files and tags referencing them. Images whose org.opencontainers.image.source
+// label links a GitHub repository are watched through the releases of the repository,
+// unless ProviderConfig.Source is registry; the others through the tags in their registry.
+func (p ImagesProvider) Repositories(ctx context.Context) ([]RepositoryConfig, error) {
+	var usages []images.Usage
+	for _, path := range p.Paths {
+		found, err := images.Find(path)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Path = filepath.ToSlash(filepath.Join(path, found[i].Path))
+		}
+		usages = append(usages, found...)
+	}
+
+	var repos []RepositoryConfig
+	byName := make(map[string]int)
+	for _, u := range usages {
+		repo := p.image(ctx, u.Reference)
+
+		i, ok := byName[repo.Name]
+		if !ok {
+			i = len(repos)
+			byName[repo.Name] = i
+			repos = append(repos, repo)
+		}
+		repos[i].References = append(repos[i].References, release.Reference{
+			Path:    u.Path,
+			Version: u.Tag,
+		})
+	}
+
+	return repos, nil
+}
+
+// image returns the RepositoryConfig to watch the given image.
+func (p ImagesProvider) image(ctx context.Context, ref images.Reference) RepositoryConfig {
+	if p.Source != "registry" {
+		if name := p.source(ctx, ref); name != "" {
+			return p.repository(name)
+		}
+	}
+
+	repo := p.repository(ref.Name())
+	repo.Type = "oci"
+	return repo
+}
+
+// source returns the GitHub repository linked by the source label of the image,
+// or an empty string. Results are cached, as labels of a tag rarely change.
+func (p ImagesProvider) source(ctx context.Context, ref images.Reference) string {
+	key := ref.Name() + ":" + ref.Tag
+	if cached, ok := p.sources.Load(key); ok {
+		if name, ok := cached.(string); ok {
+			return name
+		}
+	}
+
+	labels, err := p.Client.Labels(ctx, ref.Registry, ref.Repository, ref.Tag)
+	if err != nil {
+		slog.WarnContext(ctx, "can't get image labels", slog.String("image", key), slog.Any("err", err))
+		return ""
+	}
+
+	name := gitHubRepositoryName(labels[oci.SourceLabel])
+	p.sources.Store(key, name)
+	return name
+}
+
+// gitHubRepositoryName returns owner/repo from a URL like https://github.com/owner/repo.git,
+// or an empty string if the URL is not of a GitHub repository.
+func gitHubRepositoryName(url string) string {
+	url = strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	parts := strings.Split(strings.TrimSuffix(url, ".git"), "/")
+	if len(parts) < 3 || parts[0] != "github.com" || parts[1] == "" || parts[2] == "" {
+		return ""
+	}
+	return parts[1] + "/" + strings.TrimSuffix(parts[2], ".git")
+}
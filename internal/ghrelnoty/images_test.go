@@ -0,0 +1,101 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"it.davquar/gitrelnoty/internal/oci"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func newTestRegistry(t *testing.T) (*httptest.Server, string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/team/app/tags/list":
+			fmt.Fprint(w, `{"tags":["v1.2.0","v1.10.0","v1.9.0","v1.11.0-rc.1","main"]}`)
+		case "/v2/team/app/manifests/v1.9.0":
+			fmt.Fprint(w, `{"config":{"digest":"sha256:app"}}`)
+		case "/v2/team/app/blobs/sha256:app":
+			fmt.Fprint(w, `{"config":{"Labels":{}}}`)
+		case "/v2/team/tool/manifests/2.0":
+			fmt.Fprint(w, `{"config":{"digest":"sha256:tool"}}`)
+		case "/v2/team/tool/blobs/sha256:tool":
+			fmt.Fprint(w, `{"config":{"Labels":{"org.opencontainers.image.source":"https://github.com/team/tool.git"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestImagesProvider(t *testing.T) {
+	server, registry := newTestRegistry(t)
+
+	dir := t.TempDir()
+	compose := fmt.Sprintf("services:\n  app:\n    image: %[1]s/team/app:v1.9.0\n  tool:\n    image: %[1]s/team/tool:2.0\n", registry)
+	if err := os.WriteFile(filepath.Join(dir, "docker-compose.yaml"), []byte(compose), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := ImagesProvider{
+		ProviderConfig: ProviderConfig{
+			Paths:       []string{dir},
+			Destination: "email",
+		},
+		Client:  oci.NewClient(server.Client()),
+		sources: &sync.Map{},
+	}
+
+	repos, err := p.Repositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repos) != 2 {
+		t.Fatalf("expected 2 repositories, got %d: %+v", len(repos), repos)
+	}
+	if repos[0].Name != registry+"/team/app" || repos[0].Type != "oci" {
+		t.Errorf("unexpected repository %+v", repos[0])
+	}
+	if repos[1].Name != "team/tool" || repos[1].Type != "github" {
+		t.Errorf("unexpected repository %+v", repos[1])
+	}
+
+	refs := repos[0].ReferencesFor(release.Release{Version: "v1.10.0"}, nil)
+	if len(refs) != 1 || refs[0].Version != "v1.9.0" || !refs[0].Outdated ||
+		refs[0].Path != filepath.ToSlash(filepath.Join(dir, "docker-compose.yaml")) {
+		t.Fatalf("unexpected references %+v", refs)
+	}
+}
+
+func TestOCIRepository(t *testing.T) {
+	server, registry := newTestRegistry(t)
+
+	repo := OCIRepository{
+		RepositoryConfig: RepositoryConfig{
+			Type: "oci",
+			Name: registry + "/team/app",
+		},
+		Client: oci.NewClient(server.Client()),
+	}
+
+	releases, _, err := repo.GetReleases(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(releases) != 3 || releases[0].Version != "v1.10.0" || releases[2].Version != "v1.2.0" {
+		t.Fatalf("unexpected releases %+v", releases)
+	}
+	if releases[0].Author != registry+"/team" || releases[0].Project != "app" {
+		t.Fatalf("unexpected release %+v", releases[0])
+	}
+}
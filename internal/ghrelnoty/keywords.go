@@ -0,0 +1,60 @@
+package ghrelnoty
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// securityLabel is the label of releases routed to the security destination.
+const securityLabel = "security"
+
+// KeywordRule tags the releases whose description matches Pattern, a regex, with
+// Label and Priority (normal, high or urgent). Releases labeled security are sent to
+// the security destination of the repository, if set. There are no built-in rules.
+type KeywordRule struct {
+	Pattern  string `yaml:"pattern"`
+	Label    string `yaml:"label"`
+	Priority string `yaml:"priority"`
+
+	regex    *regexp.Regexp
+	priority release.Priority
+}
+
+// validateKeywords returns an error if any of the rules can't be used, and compiles
+// their patterns otherwise. It must be called before the rules are used.
+func validateKeywords(rules []KeywordRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid keyword pattern %s: %w", rule.Pattern, err)
+		}
+		priority, err := release.ParsePriority(rule.Priority)
+		if err != nil {
+			return fmt.Errorf("keyword pattern %s: %w", rule.Pattern, err)
+		}
+		rule.regex, rule.priority = re, priority
+	}
+	return nil
+}
+
+// prioritize sets the Priority and the Labels of the release according to the global
+// keyword rules and the ones of the repository. The priority is never lowered.
+func (s Service) prioritize(repo RepositoryConfig, r release.Release) release.Release {
+	for _, rule := range slices.Concat(s.Config.Keywords, repo.Keywords) {
+		if rule.regex == nil || !rule.regex.MatchString(r.Description) {
+			continue
+		}
+
+		if rule.priority > r.Priority {
+			r.Priority = rule.priority
+		}
+		if rule.Label != "" && !slices.Contains(r.Labels, rule.Label) {
+			r.Labels = append(r.Labels, rule.Label)
+		}
+	}
+	return r
+}
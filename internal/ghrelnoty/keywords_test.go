@@ -0,0 +1,61 @@
+package ghrelnoty
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestNotifyKeywords(t *testing.T) {
+	s, err := New(Config{
+		DBPath: filepath.Join(t.TempDir(), "ghrelnoty.db"),
+		Keywords: []KeywordRule{
+			{Pattern: `CVE-\d+-\d+`, Label: "security", Priority: "urgent"},
+			{Pattern: `(?i)breaking change`, Label: "breaking", Priority: "high"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	defer s.Close()
+
+	email := make(chanNotifier, 2)
+	security := make(chanNotifier, 2)
+	s.Notifiers = map[string]Notifier{"email": email, "security": security}
+
+	repo := RepositoryConfig{
+		Name:                "author/name",
+		Destination:         "email",
+		SecurityDestination: "security",
+		Keywords:            []KeywordRule{{Pattern: `(?i)data loss`, Label: "data-loss", Priority: "high"}},
+	}
+	if err := validateKeywords(repo.Keywords); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = s.notify(repo, release.Release{Version: "1.2.0", Description: "## Breaking changes\n\nFixes data loss on restart."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := <-email
+	if r.Priority != release.PriorityHigh || !slices.Equal(r.Labels, []string{"breaking", "data-loss"}) {
+		t.Fatalf("unexpected priority %s and labels %v", r.Priority, r.Labels)
+	}
+
+	err = s.notify(repo, release.Release{Version: "1.2.1", Description: "Fixes CVE-2025-1234."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(email) != 0 || len(security) != 1 {
+		t.Fatalf("expected the release to be routed to security")
+	}
+	if r := <-security; r.Priority != release.PriorityUrgent {
+		t.Fatalf("expected urgent priority, got %s", r.Priority)
+	}
+
+	if err := validateKeywords([]KeywordRule{{Pattern: "x", Priority: "critical"}}); err == nil {
+		t.Fatal("expected error for invalid priority")
+	}
+}
@@ -0,0 +1,136 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// MetadataGetter is implemented by Releasers that can get the metadata of
+// their repository.
+type MetadataGetter interface {
+	GetMetadata(context.Context) (store.Metadata, error)
+}
+
+var deprecationRegex = regexp.MustCompile(`(?i)\b(deprecated|unmaintained|no longer maintained|not maintained|moved to|superseded by)\b`)
+
+// GetMetadata gets the current metadata of the repository, following redirects
+// of renamed and transferred repositories.
+func (r GitHubRepository) GetMetadata(ctx context.Context) (store.Metadata, error) {
+	author, repo := r.SeparateName()
+	repository, _, err := r.Client.Repositories.Get(ctx, author, repo)
+	if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+		return store.Metadata{}, rateLimitErr
+	}
+	if err != nil {
+		return store.Metadata{}, fmt.Errorf("%s: %w", r.Name, err)
+	}
+
+	return store.Metadata{
+		FullName:      repository.GetFullName(),
+		Archived:      repository.GetArchived(),
+		DefaultBranch: repository.GetDefaultBranch(),
+		Description:   repository.GetDescription(),
+		Deprecated:    deprecationRegex.MatchString(repository.GetDescription()),
+	}, nil
+}
+
+// checkLifecycle notifies, once, if the repository was archived, renamed, transferred
+// or deprecated since the previous check. The first time, the configured name is
+// considered the previous one. All the stored data of renamed repositories is moved
+// under their new name, if RepositoryConfig.FollowRenames is set.
+func (s Service) checkLifecycle(ctx context.Context, repo Releaser) error {
+	getter, ok := repo.(MetadataGetter)
+	if !ok || !repo.Config().Lifecycle {
+		return nil
+	}
+	name := repo.Config().Name
+
+	current, err := getter.GetMetadata(ctx)
+	if err != nil {
+		metrics.CannotGetRelease()
+		slog.ErrorContext(ctx, "can't get repository metadata", slog.String("repo", name), slog.Any("err", err))
+		return err
+	}
+
+	key, err := s.Store.ResolveKey(name)
+	if err != nil {
+		metrics.DBError()
+		return err
+	}
+	previous, ok, err := s.Store.GetMetadata(key)
+	if err != nil {
+		metrics.DBError()
+		return err
+	}
+	if !ok {
+		previous = store.Metadata{FullName: name, DefaultBranch: current.DefaultBranch}
+	}
+
+	changes := lifecycleChanges(previous, current)
+	if previous.DefaultBranch != current.DefaultBranch {
+		slog.InfoContext(ctx, "default branch changed", slog.String("repo", name),
+			slog.String("from", previous.DefaultBranch), slog.String("to", current.DefaultBranch))
+	}
+
+	err = s.Store.SetMetadata(key, current)
+	if err != nil {
+		metrics.DBError()
+		return err
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	metrics.LifecycleEvent()
+	slog.WarnContext(ctx, "repository lifecycle changed", slog.String("repo", name), slog.Any("changes", changes))
+
+	if repo.Config().FollowRenames && !strings.EqualFold(previous.FullName, current.FullName) {
+		if err := s.Store.Rename(key, current.FullName); err != nil {
+			metrics.DBError()
+			return err
+		}
+	}
+
+	author, project := repo.Config().SeparateName()
+	return s.notify(repo.Config(), release.Release{
+		Kind:        release.KindLifecycle,
+		Project:     project,
+		Author:      author,
+		Version:     current.FullName,
+		Description: current.Description,
+		URL:         "https://github.com/" + current.FullName,
+		Changes:     changes,
+	})
+}
+
+// lifecycleChanges describes the lifecycle events between two Metadata.
+func lifecycleChanges(previous store.Metadata, current store.Metadata) []string {
+	var changes []string
+
+	if !previous.Archived && current.Archived {
+		changes = append(changes, "repository was archived")
+	}
+
+	if !strings.EqualFold(previous.FullName, current.FullName) {
+		prevOwner, _, _ := strings.Cut(previous.FullName, "/")
+		owner, _, _ := strings.Cut(current.FullName, "/")
+		if strings.EqualFold(prevOwner, owner) {
+			changes = append(changes, fmt.Sprintf("repository was renamed from %s to %s", previous.FullName, current.FullName))
+		} else {
+			changes = append(changes, fmt.Sprintf("repository was transferred from %s to %s", previous.FullName, current.FullName))
+		}
+	}
+
+	if !previous.Deprecated && current.Deprecated {
+		changes = append(changes, fmt.Sprintf("description mentions a deprecation: %s", current.Description))
+	}
+
+	return changes
+}
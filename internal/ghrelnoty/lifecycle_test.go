@@ -0,0 +1,107 @@
+package ghrelnoty
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestLifecycleChanges(t *testing.T) {
+	previous := store.Metadata{FullName: "author/name"}
+
+	cases := map[string]store.Metadata{
+		"repository was archived":                                     {FullName: "author/name", Archived: true},
+		"repository was renamed from author/name to author/newname":   {FullName: "author/newname"},
+		"repository was transferred from author/name to other/name":   {FullName: "other/name"},
+		"description mentions a deprecation: Deprecated, use foo/bar": {FullName: "author/name", Description: "Deprecated, use foo/bar", Deprecated: true},
+	}
+
+	for expected, current := range cases {
+		changes := lifecycleChanges(previous, current)
+		if len(changes) != 1 || changes[0] != expected {
+			t.Errorf("expected [%s], got %v", expected, changes)
+		}
+	}
+
+	if changes := lifecycleChanges(previous, store.Metadata{FullName: "Author/Name"}); len(changes) != 0 {
+		t.Errorf("expected no changes for different case, got %v", changes)
+	}
+}
+
+type dummyMetadataReleaser struct {
+	dummyReleaser
+	metadata store.Metadata
+}
+
+func (r dummyMetadataReleaser) GetMetadata(_ context.Context) (store.Metadata, error) {
+	return r.metadata, nil
+}
+
+func TestCheckLifecycleFollowRenames(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+
+	if err := s.Store.Set("author/name", store.Record{Version: "1.2.3"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := dummyMetadataReleaser{
+		dummyReleaser: dummyReleaser{RepositoryConfig{
+			Name:          "author/name",
+			Destination:   "chan",
+			Lifecycle:     true,
+			FollowRenames: true,
+		}},
+		metadata: store.Metadata{FullName: "author/newname"},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.checkLifecycle(context.Background(), repo); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+
+	key, err := s.Store.ResolveKey("author/name")
+	if err != nil || key != "author/newname" {
+		t.Fatalf("expected key author/newname, got %s, %v", key, err)
+	}
+
+	record, err := s.Store.Get(key)
+	if err != nil || record.Version != "1.2.3" {
+		t.Fatalf("expected record to be moved, got %+v, %v", record, err)
+	}
+}
+
+// movedReleaser is a repository whose releases can't be listed anymore.
+type movedReleaser struct {
+	dummyMetadataReleaser
+}
+
+func (r movedReleaser) GetReleases(_ context.Context) ([]release.Release, RateLimitData, error) {
+	return nil, RateLimitData{}, errors.New("404 Not Found")
+}
+
+func TestWorkChecksLifecycleWithoutReleases(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	s.Releasers = []Releaser{movedReleaser{dummyMetadataReleaser{
+		dummyReleaser: dummyReleaser{RepositoryConfig{Name: "author/name", Destination: "chan", Lifecycle: true}},
+		metadata:      store.Metadata{FullName: "other/name"},
+	}}}
+
+	c := make(chan error)
+	s.Work(c)
+
+	if len(notifiers["chan"]) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifiers["chan"]))
+	}
+	if r := <-notifiers["chan"]; r.Kind != release.KindLifecycle {
+		t.Fatalf("expected a lifecycle notification, got %+v", r)
+	}
+}
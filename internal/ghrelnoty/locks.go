@@ -0,0 +1,52 @@
+package ghrelnoty
+
+import (
+	"strings"
+	"sync"
+)
+
+// repoLocks serializes the processing of each repository, so that Work and Check
+// don't both notify the same release.
+type repoLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks the repository with the given name, case-insensitively, and returns
+// the function that unlocks it.
+func (l *repoLocks) lock(name string) func() {
+	key := strings.ToLower(name)
+
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	m, ok := l.locks[key]
+	if !ok {
+		m = &sync.Mutex{}
+		l.locks[key] = m
+	}
+	l.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
+
+// watched holds the releasers listed by the last Work, so that Check doesn't list
+// the repositories of all the providers again.
+type watched struct {
+	mu        sync.RWMutex
+	releasers []Releaser
+}
+
+func (w *watched) get() []Releaser {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.releasers
+}
+
+func (w *watched) set(releasers []Releaser) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.releasers = releasers
+}
@@ -0,0 +1,115 @@
+package ghrelnoty
+
+import (
+	"fmt"
+	"slices"
+
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// detectMutations compares the releases (given newest first) with the fingerprint of
+// an already announced release, returning a description of each change to it: a moved
+// tag, replaced or removed assets, or the deletion of the release. Deletions are
+// detected only if the releases go back to the publication of the announced one.
+func detectMutations(releases []release.Release, announced store.Fingerprint) []string {
+	if announced.Tag == "" || len(releases) == 0 {
+		return nil
+	}
+
+	i := slices.IndexFunc(releases, func(r release.Release) bool {
+		return r.Tag == announced.Tag
+	})
+	if i < 0 {
+		oldest := releases[len(releases)-1]
+		if announced.PublishedAt.IsZero() || oldest.PublishedAt.After(announced.PublishedAt) {
+			return nil
+		}
+		return []string{fmt.Sprintf("release %s was deleted", announced.Tag)}
+	}
+	r := releases[i]
+
+	var mutations []string
+	if announced.Commit != "" && r.Commit != "" && announced.Commit != r.Commit {
+		mutations = append(mutations, fmt.Sprintf("tag %s moved from %s to %s", r.Tag, announced.Commit, r.Commit))
+	}
+
+	digests := assetDigests(r.Assets)
+	names := make([]string, 0, len(announced.Assets))
+	for name := range announced.Assets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		digest, ok := digests[name]
+		switch {
+		case !slices.ContainsFunc(r.Assets, func(a release.Asset) bool { return a.Name == name }):
+			mutations = append(mutations, fmt.Sprintf("asset %s was removed", name))
+		case ok && digest != announced.Assets[name]:
+			mutations = append(mutations, fmt.Sprintf("asset %s was replaced: %s, was %s", name, digest, announced.Assets[name]))
+		}
+	}
+
+	return mutations
+}
+
+// announced returns the Fingerprints of the announced releases of the current record:
+// the one it describes, unless it's legacy, and the other ones it watches.
+func announced(current store.Record) []store.Fingerprint {
+	if current.Tag == "" || current.Legacy {
+		return current.Watched
+	}
+	return append([]store.Fingerprint{{
+		Version:     current.Version,
+		Tag:         current.Tag,
+		PublishedAt: current.PublishedAt,
+		Commit:      current.Commit,
+		Assets:      current.Assets,
+	}}, current.Watched...)
+}
+
+// fingerprints returns the Fingerprints of the announced releases to watch besides the
+// one with the given tag, which is described by the record itself: the releases
+// announced before and the notified ones, as they are now among the given releases
+// (newest first). Releases not listed anymore are dropped, and so are the oldest
+// ones beyond releasesPerPage.
+func fingerprints(releases []release.Release, current store.Record, notified []release.Release, tag string) []store.Fingerprint {
+	tags := make(map[string]bool)
+	for _, f := range announced(current) {
+		tags[f.Tag] = true
+	}
+	for _, r := range notified {
+		tags[r.Tag] = true
+	}
+
+	var found []store.Fingerprint
+	for _, r := range releases {
+		if r.Tag == tag || !tags[r.Tag] {
+			continue
+		}
+		found = append(found, store.Fingerprint{
+			Version:     r.Version,
+			Tag:         r.Tag,
+			PublishedAt: r.PublishedAt,
+			Commit:      r.Commit,
+			Assets:      assetDigests(r.Assets),
+		})
+		if len(found) == releasesPerPage {
+			break
+		}
+	}
+	return found
+}
+
+// assetDigests returns the digests of the given assets by name, skipping the
+// assets without digest.
+func assetDigests(assets []release.Asset) map[string]string {
+	digests := make(map[string]string, len(assets))
+	for _, a := range assets {
+		if a.Digest != "" {
+			digests[a.Name] = a.Digest
+		}
+	}
+	return digests
+}
@@ -0,0 +1,114 @@
+package ghrelnoty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestDetectMutations(t *testing.T) {
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := store.Fingerprint{
+		Version:     "1.2.3",
+		Tag:         "v1.2.3",
+		PublishedAt: published,
+		Commit:      "aaaa",
+		Assets: map[string]string{
+			"app_linux_amd64.tar.gz":  "sha256:1111",
+			"app_darwin_arm64.tar.gz": "sha256:2222",
+			"checksums.txt":           "sha256:3333",
+		},
+	}
+
+	releases := []release.Release{{
+		Tag:         "v1.2.3",
+		PublishedAt: published,
+		Commit:      "bbbb",
+		Assets: []release.Asset{
+			{Name: "app_linux_amd64.tar.gz", Digest: "sha256:9999"},
+			{Name: "checksums.txt", Digest: "sha256:3333"},
+			{Name: "app_windows_amd64.zip", Digest: "sha256:4444"},
+		},
+	}}
+
+	mutations := detectMutations(releases, current)
+	expected := []string{
+		"tag v1.2.3 moved from aaaa to bbbb",
+		"asset app_darwin_arm64.tar.gz was removed",
+		"asset app_linux_amd64.tar.gz was replaced: sha256:9999, was sha256:1111",
+	}
+	if len(mutations) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, mutations)
+	}
+	for i := range expected {
+		if mutations[i] != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], mutations[i])
+		}
+	}
+}
+
+func TestDetectMutationsDeleted(t *testing.T) {
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := store.Fingerprint{Version: "1.2.3", Tag: "v1.2.3", PublishedAt: published}
+
+	older := []release.Release{{Tag: "v1.2.2", PublishedAt: published.Add(-time.Hour)}}
+	if mutations := detectMutations(older, current); len(mutations) != 1 {
+		t.Fatalf("expected deletion, got %v", mutations)
+	}
+
+	newer := []release.Release{{Tag: "v1.3.0", PublishedAt: published.Add(time.Hour)}}
+	if mutations := detectMutations(newer, current); len(mutations) != 0 {
+		t.Fatalf("expected no mutations when the release might be on another page, got %v", mutations)
+	}
+}
+
+func TestProcessWatchesAllAnnouncedReleases(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+
+	t0 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := RepositoryConfig{Name: "author/name", Destination: "chan", DetectMutations: true}
+	releases := []release.Release{{Version: "1.0.0", Tag: "v1.0.0", PublishedAt: t0, Commit: "aaaa"}}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-notifications
+
+	// Two releases at once: both are announced, and 1.0.0 is still watched.
+	releases = append([]release.Release{
+		{Version: "1.2.0", Tag: "v1.2.0", PublishedAt: t0.Add(2 * time.Hour), Commit: "cccc"},
+		{Version: "1.1.0", Tag: "v1.1.0", PublishedAt: t0.Add(time.Hour), Commit: "bbbb"},
+	}, releases...)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifications))
+	}
+	<-notifications
+	<-notifications
+
+	releases[1].Commit = "dddd"
+	releases[2].Commit = "eeee"
+	for i := 0; i < 2; i++ {
+		if err := s.process(context.Background(), repo, releases); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 mutations, notified once, got %d", len(notifications))
+	}
+	for _, tag := range []string{"v1.1.0", "v1.0.0"} {
+		if r := <-notifications; r.Kind != release.KindMutated || r.Tag != tag {
+			t.Fatalf("expected mutation of %s, got %+v", tag, r)
+		}
+	}
+
+	record, err := s.Store.Get("author/name")
+	if err != nil || record.Tag != "v1.2.0" || len(record.Watched) != 2 {
+		t.Fatalf("expected 1.2.0 with 2 watched releases, got %+v, %v", record, err)
+	}
+}
@@ -0,0 +1,61 @@
+package ghrelnoty
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// muted returns true if the notification of the given release is silenced by the
+// Mute stored for the given repository. Errors reading it are logged, and the
+// notification goes out.
+func (s Service) muted(repo RepositoryConfig, r release.Release) bool {
+	key, err := s.Store.ResolveKey(repo.Name)
+	if err != nil {
+		metrics.DBError()
+		slog.Error("can't read from db", slog.String("repo", repo.Name), slog.Any("err", err))
+		return false
+	}
+	mute, err := s.Store.GetMute(key)
+	if err != nil {
+		metrics.DBError()
+		slog.Error("can't read mute from db", slog.String("repo", repo.Name), slog.Any("err", err))
+		return false
+	}
+	if !mute.Silences(r.Version, r.Tag, time.Now()) {
+		return false
+	}
+
+	metrics.NotificationMuted()
+	slog.Info("notification muted", slog.String("repo", repo.Name), slog.String("release", r.Version), slog.String("kind", string(r.Kind)))
+	return true
+}
+
+// ParseUntil returns the time a snooze ends, given either an absolute time (RFC 3339,
+// or a date in the local timezone) or a duration from now. The zero time, meaning
+// muted until unmuted, is returned if both are empty.
+func ParseUntil(until string, duration string, now time.Time) (time.Time, error) {
+	switch {
+	case until != "" && duration != "":
+		return time.Time{}, fmt.Errorf("until and duration can't be both set")
+	case duration != "":
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration %s: %w", duration, err)
+		}
+		return now.Add(d), nil
+	case until != "":
+		if t, err := time.Parse(time.RFC3339, until); err == nil {
+			return t, nil
+		}
+		t, err := time.ParseInLocation(time.DateOnly, until, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %s: use RFC 3339 or YYYY-MM-DD", until)
+		}
+		return t, nil
+	}
+	return time.Time{}, nil
+}
@@ -0,0 +1,128 @@
+package ghrelnoty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		until    string
+		duration string
+		want     time.Time
+		wantErr  bool
+	}{
+		{"", "", time.Time{}, false},
+		{"", "72h", now.Add(72 * time.Hour), false},
+		{"2025-04-01T00:00:00Z", "", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2025-04-01", "", time.Date(2025, 4, 1, 0, 0, 0, 0, time.Local), false},
+		{"2025-04-01", "72h", time.Time{}, true},
+		{"tomorrow", "", time.Time{}, true},
+		{"", "3 days", time.Time{}, true},
+	}
+
+	for _, c := range cases {
+		got, err := ParseUntil(c.until, c.duration, now)
+		if (err != nil) != c.wantErr || !got.Equal(c.want) {
+			t.Errorf("ParseUntil(%q, %q): expected %v (error %t), got %v, %v", c.until, c.duration, c.want, c.wantErr, got, err)
+		}
+	}
+}
+
+func TestProcessMuted(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+	repo := RepositoryConfig{Name: "author/name", Destination: "chan"}
+
+	// Repository names are case-insensitive, as on GitHub.
+	if err := s.Store.Ignore("Author/Name", "1.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Store.Mute("author/other", time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var releases []release.Release
+	for _, version := range []string{"1.0.0", "1.0.1", "1.0.2"} {
+		releases = append([]release.Release{{Version: version, Tag: "v" + version}}, releases...)
+		if err := s.process(context.Background(), repo, releases); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifications))
+	}
+	for _, want := range []string{"1.0.0", "1.0.2"} {
+		if r := <-notifications; r.Version != want {
+			t.Fatalf("expected %s, got %s", want, r.Version)
+		}
+	}
+}
+
+func TestAPIMutes(t *testing.T) {
+	s, _ := newTestService(t)
+	s.Config.APIToken = "token"
+	handler := s.APIHandler()
+
+	requests := []struct {
+		target string
+		token  string
+		status int
+	}{
+		{"/api/mute?repository=author/name&for=1h", "", http.StatusUnauthorized},
+		{"/api/mute?repository=author/name&for=1h", "wrong", http.StatusUnauthorized},
+		{"/api/mute?repository=author/name&for=soon", "token", http.StatusBadRequest},
+		{"/api/mute?repository=author/name&for=1h", "token", http.StatusNoContent},
+		{"/api/ignore?repository=author/name", "token", http.StatusBadRequest},
+		{"/api/ignore?repository=author/name&version=1.2.3", "token", http.StatusNoContent},
+		{"/api/ignore?repository=author/other&version=2.0.0", "token", http.StatusNoContent},
+		{"/api/unignore?repository=author/other&version=2.0.0", "token", http.StatusNoContent},
+	}
+	for _, r := range requests {
+		req := httptest.NewRequest(http.MethodPost, r.target, nil)
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != r.status {
+			t.Fatalf("POST %s: expected status %d, got %d", r.target, r.status, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/mutes", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(rec, req)
+	var mutes []muteStatus
+	if err := json.NewDecoder(rec.Body).Decode(&mutes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mutes) != 1 || mutes[0].Repository != "author/name" || mutes[0].Muted || mutes[0].Until.IsZero() || len(mutes[0].Ignored) != 1 {
+		t.Fatalf("expected a snoozed author/name ignoring 1.2.3, got %+v", mutes)
+	}
+}
+
+func TestAPIWithoutToken(t *testing.T) {
+	s, _ := newTestService(t)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/mute?repository=author/name", nil),
+		httptest.NewRequest(http.MethodGet, "/api/mutes", nil),
+	} {
+		req.Header.Set("Authorization", "Bearer ")
+		rec := httptest.NewRecorder()
+		s.APIHandler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s %s: expected status 401 without api token, got %d", req.Method, req.URL, rec.Code)
+		}
+	}
+}
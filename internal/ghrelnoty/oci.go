@@ -0,0 +1,94 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"it.davquar/gitrelnoty/internal/oci"
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// registryClient is shared by the releasers and providers using OCI registries.
+var registryClient = oci.NewClient(httpClient)
+
+// OCIRepository gets the tags of a container image from its registry. RepositoryConfig.Name
+// is the image name including the registry, like docker.io/library/nginx.
+type OCIRepository struct {
+	RepositoryConfig
+	Client *oci.Client
+}
+
+func (r OCIRepository) Config() RepositoryConfig {
+	return r.RepositoryConfig
+}
+
+// GetReleases gets the semver tags of the image, newest first. Prereleases are
+// filtered according to RepositoryConfig.Prereleases. Registries don't expose
+// when tags are pushed, so releases have no publication time.
+func (r OCIRepository) GetReleases(ctx context.Context) ([]release.Release, RateLimitData, error) {
+	registry, repository, ok := strings.Cut(r.Name, "/")
+	if !ok {
+		return nil, RateLimitData{}, fmt.Errorf("%s: image name without registry", r.Name)
+	}
+
+	tags, err := r.Client.Tags(ctx, registry, repository)
+	if err != nil {
+		return nil, RateLimitData{}, fmt.Errorf("%s: %w", r.Name, err)
+	}
+
+	type tagVersion struct {
+		tag     string
+		version semver.Version
+	}
+	var versions []tagVersion
+	for _, tag := range tags {
+		v, err := semver.Parse(tag)
+		if err != nil || !r.WantsRelease(v.Prerelease != "") {
+			continue
+		}
+		versions = append(versions, tagVersion{tag, v})
+	}
+	slices.SortStableFunc(versions, func(a, b tagVersion) int {
+		return b.version.Compare(a.version)
+	})
+	if len(versions) > releasesPerPage {
+		versions = versions[:releasesPerPage]
+	}
+
+	author, project := repository, repository
+	if i := strings.LastIndex(r.Name, "/"); i >= 0 {
+		author, project = r.Name[:i], r.Name[i+1:]
+	}
+
+	releases := make([]release.Release, 0, len(versions))
+	for _, v := range versions {
+		version, err := r.Extract(v.tag, "")
+		if err != nil {
+			return nil, RateLimitData{}, fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		releases = append(releases, release.Release{
+			Kind:       release.KindNew,
+			Project:    project,
+			Author:     author,
+			Version:    version,
+			Tag:        v.tag,
+			URL:        imageURL(registry, repository),
+			Prerelease: v.version.Prerelease != "",
+		})
+	}
+
+	return releases, RateLimitData{}, nil
+}
+
+// imageURL returns the web page of the image, which is on Docker Hub or
+// under the registry host for the others.
+func imageURL(registry string, repository string) string {
+	if registry == "docker.io" {
+		return "https://hub.docker.com/r/" + strings.Replace(repository, "library/", "_/", 1) + "/tags"
+	}
+	return "https://" + registry + "/" + repository
+}
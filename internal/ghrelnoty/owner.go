@@ -0,0 +1,144 @@
+package ghrelnoty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// GitHubOwnerProvider lists the repositories of a GitHub user or organization.
+type GitHubOwnerProvider struct {
+	ProviderConfig
+	Client *github.Client
+
+	// kinds caches the ownerKind of users, by login.
+	kinds *sync.Map
+}
+
+// ownerKind is how the repositories of an owner are listed.
+type ownerKind int
+
+const (
+	ownerUser ownerKind = iota
+	ownerOrganization
+	ownerAuthenticated
+)
+
+func (p GitHubOwnerProvider) Config() ProviderConfig {
+	return p.ProviderConfig
+}
+
+// Repositories returns the repositories of ProviderConfig.User that pass the OwnerFilters,
+// following pagination. Private repositories are included if the token can see them:
+// those of the authenticated user are listed as such, as they are hidden otherwise.
+func (p GitHubOwnerProvider) Repositories(ctx context.Context) ([]RepositoryConfig, error) {
+	kind, err := p.kind(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []*github.Repository
+	opts := github.ListOptions{PerPage: 100}
+	for {
+		var (
+			page []*github.Repository
+			resp *github.Response
+		)
+
+		switch kind {
+		case ownerOrganization:
+			page, resp, err = p.Client.Repositories.ListByOrg(ctx, p.User, &github.RepositoryListByOrgOptions{Type: "all", ListOptions: opts})
+		case ownerAuthenticated:
+			page, resp, err = p.Client.Repositories.List(ctx, "", &github.RepositoryListOptions{Affiliation: "owner", ListOptions: opts})
+		default:
+			page, resp, err = p.Client.Repositories.ListByUser(ctx, p.User, &github.RepositoryListByUserOptions{Type: "owner", ListOptions: opts})
+		}
+
+		if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+			return nil, rateLimitErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.User, err)
+		}
+
+		all = append(all, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return p.filter(all)
+}
+
+// kind returns the ownerKind of ProviderConfig.User. It's cached once known, as it
+// doesn't change; if the authenticated user can't be fetched, like without a token,
+// the owner is listed as any other user.
+func (p GitHubOwnerProvider) kind(ctx context.Context) (ownerKind, error) {
+	if cached, ok := p.kinds.Load(p.User); ok {
+		if kind, ok := cached.(ownerKind); ok {
+			return kind, nil
+		}
+	}
+
+	user, _, err := p.Client.Users.Get(ctx, p.User)
+	if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+		return ownerUser, rateLimitErr
+	}
+	if err != nil {
+		return ownerUser, fmt.Errorf("%s: %w", p.User, err)
+	}
+	if user.GetType() == "Organization" {
+		p.kinds.Store(p.User, ownerOrganization)
+		return ownerOrganization, nil
+	}
+
+	authenticated, _, err := p.Client.Users.Get(ctx, "")
+	if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+		return ownerUser, rateLimitErr
+	}
+	if err != nil {
+		slog.DebugContext(ctx, "can't get authenticated user", slog.Any("err", err))
+		return ownerUser, nil
+	}
+
+	kind := ownerUser
+	if strings.EqualFold(authenticated.GetLogin(), user.GetLogin()) {
+		kind = ownerAuthenticated
+	}
+	p.kinds.Store(p.User, kind)
+	return kind, nil
+}
+
+func (p GitHubOwnerProvider) filter(all []*github.Repository) ([]RepositoryConfig, error) {
+	if p.NameRegex != "" && p.nameRegex == nil {
+		return nil, errors.New("name regex not validated")
+	}
+
+	repos := make([]RepositoryConfig, 0, len(all))
+	for _, r := range all {
+		if r.GetArchived() && !p.Archived {
+			continue
+		}
+		if r.GetFork() && !p.Forks {
+			continue
+		}
+		if len(p.Topics) > 0 && !slices.ContainsFunc(r.Topics, func(topic string) bool {
+			return slices.Contains(p.Topics, topic)
+		}) {
+			continue
+		}
+		if p.nameRegex != nil && !p.nameRegex.MatchString(r.GetName()) {
+			continue
+		}
+
+		repos = append(repos, p.repository(r.GetFullName()))
+	}
+	return repos, nil
+}
@@ -0,0 +1,152 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestOwnerFilter(t *testing.T) {
+	all := []*github.Repository{
+		{FullName: github.Ptr("owner/kind"), Name: github.Ptr("kind"), Topics: []string{"k8s"}},
+		{FullName: github.Ptr("owner/kind-archived"), Name: github.Ptr("kind-archived"), Archived: github.Ptr(true), Topics: []string{"k8s"}},
+		{FullName: github.Ptr("owner/kind-fork"), Name: github.Ptr("kind-fork"), Fork: github.Ptr(true), Topics: []string{"k8s"}},
+		{FullName: github.Ptr("owner/no-topics"), Name: github.Ptr("no-topics")},
+		{FullName: github.Ptr("owner/other"), Name: github.Ptr("other"), Topics: []string{"k8s"}},
+	}
+
+	p := GitHubOwnerProvider{
+		ProviderConfig: ProviderConfig{
+			Destination: "email",
+			OwnerFilters: OwnerFilters{
+				Topics:    []string{"k8s", "kubernetes"},
+				NameRegex: "^kind",
+			},
+		},
+	}
+
+	if _, err := p.filter(all); err == nil {
+		t.Fatal("expected an error before validation")
+	}
+	if err := p.OwnerFilters.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repos, err := p.filter(all)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repos) != 1 {
+		t.Fatalf("expected 1 repository, got %d: %v", len(repos), repos)
+	}
+
+	if repos[0].Name != "owner/kind" || repos[0].Destination != "email" {
+		t.Fatalf("expected owner/kind with destination email, got %v", repos[0])
+	}
+
+	p.Archived = true
+	p.Forks = true
+	repos, err = p.filter(all)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repos) != 3 {
+		t.Fatalf("expected 3 repositories including archived and forks, got %d", len(repos))
+	}
+}
+
+func TestOwnerRepositoriesAuthenticated(t *testing.T) {
+	var userCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/me":
+			userCalls.Add(1)
+			fmt.Fprint(w, `{"login":"Me","type":"User"}`)
+		case "/user":
+			fmt.Fprint(w, `{"login":"me","type":"User"}`)
+		case "/user/repos":
+			if r.URL.Query().Get("affiliation") != "owner" {
+				t.Errorf("expected owner affiliation, got %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"full_name":"me/private","name":"private","private":true}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.Client())
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.BaseURL = baseURL
+
+	p := GitHubOwnerProvider{ProviderConfig: ProviderConfig{User: "me"}, Client: client, kinds: &sync.Map{}}
+	for i := 0; i < 2; i++ {
+		repos, err := p.Repositories(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repos) != 1 || repos[0].Name != "me/private" {
+			t.Fatalf("expected the private repository, got %v", repos)
+		}
+	}
+	if calls := userCalls.Load(); calls != 1 {
+		t.Fatalf("expected the owner to be fetched once, got %d calls", calls)
+	}
+}
+
+func TestOwnerEntryKeepsRepositorySettings(t *testing.T) {
+	s, _ := newTestService(t)
+	s.Config.Repositories = []RepositoryConfig{{
+		Type:            "github",
+		Owner:           "owner",
+		Destination:     "email",
+		Prereleases:     "include",
+		RequireAssets:   "*.tar.gz",
+		MinAge:          time.Hour,
+		DetectMutations: true,
+		Backports:       true,
+		Tracks:          []Track{{Name: "lts", Line: "1"}},
+		OwnerFilters:    OwnerFilters{Forks: true},
+	}}
+	if err := s.initProviders(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p, ok := s.Providers[0].(GitHubOwnerProvider)
+	if !ok {
+		t.Fatalf("expected GitHubOwnerProvider, got %T", s.Providers[0])
+	}
+	if !p.Forks {
+		t.Fatal("expected the owner filters to be kept")
+	}
+
+	repo := p.repository("owner/name")
+	if repo.Name != "owner/name" || repo.Owner != "" || repo.Destination != "email" || repo.Prereleases != "include" ||
+		repo.RequireAssets != "*.tar.gz" || repo.MinAge != time.Hour || !repo.DetectMutations || !repo.Backports || len(repo.Tracks) != 1 {
+		t.Fatalf("expected the settings of the owner entry, got %+v", repo)
+	}
+
+	s.Config.Repositories[0].Prereleases = "sometimes"
+	if err := s.initProviders(); err == nil {
+		t.Fatal("expected an invalid owner entry to fail at startup")
+	}
+
+	s.Config.Repositories[0].Prereleases = ""
+	s.Config.Repositories[0].NameRegex = "("
+	if err := s.initProviders(); err == nil {
+		t.Fatal("expected an invalid name regex to fail at startup")
+	}
+}
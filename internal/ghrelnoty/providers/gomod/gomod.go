@@ -0,0 +1,86 @@
+package gomod
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Requirement is a direct requirement of a go.mod file.
+type Requirement struct {
+	Module  string
+	Version string
+	Path    string
+}
+
+// Find returns the direct Requirements of the go.mod files matching the given
+// glob patterns.
+func Find(patterns []string) ([]Requirement, error) {
+	var requirements []Requirement
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("glob %s: %w", pattern, err)
+		}
+
+		for _, path := range paths {
+			found, err := Parse(path)
+			if err != nil {
+				return nil, err
+			}
+			requirements = append(requirements, found...)
+		}
+	}
+	return requirements, nil
+}
+
+// Parse returns the direct Requirements of the given go.mod file, skipping the
+// ones marked as indirect.
+func Parse(path string) ([]Requirement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var (
+		requirements []Requirement
+		inBlock      bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+
+		switch {
+		case inBlock && len(fields) == 1 && fields[0] == ")":
+			inBlock = false
+			continue
+		case len(fields) == 2 && fields[0] == "require" && fields[1] == "(":
+			inBlock = true
+			continue
+		case len(fields) == 3 && fields[0] == "require":
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+
+		if len(fields) != 2 || strings.TrimSpace(comment) == "indirect" {
+			continue
+		}
+		requirements = append(requirements, Requirement{
+			Module:  strings.Trim(fields[0], `"`),
+			Version: fields[1],
+			Path:    filepath.ToSlash(path),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return requirements, nil
+}
@@ -0,0 +1,27 @@
+package gomod
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	requirements, err := Find([]string{"testdata/services/*/go.mod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Requirement{
+		{Module: "go.etcd.io/bbolt", Version: "v1.3.10", Path: "testdata/services/bar/go.mod"},
+		{Module: "github.com/google/go-github/v68", Version: "v68.0.0", Path: "testdata/services/foo/go.mod"},
+		{Module: "github.com/yuin/goldmark", Version: "v1.7.13", Path: "testdata/services/foo/go.mod"},
+		{Module: "go.etcd.io/bbolt", Version: "v1.3.11", Path: "testdata/services/foo/go.mod"},
+		{Module: "gopkg.in/yaml.v3", Version: "v3.0.1", Path: "testdata/services/foo/go.mod"},
+	}
+
+	if len(requirements) != len(expected) {
+		t.Fatalf("expected %d requirements, got %d: %v", len(expected), len(requirements), requirements)
+	}
+	for i := range expected {
+		if requirements[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], requirements[i])
+		}
+	}
+}
@@ -0,0 +1,5 @@
+module example.com/services/bar
+
+go 1.22
+
+require go.etcd.io/bbolt v1.3.10
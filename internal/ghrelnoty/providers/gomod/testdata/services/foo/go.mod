@@ -0,0 +1,16 @@
+module example.com/services/foo
+
+go 1.22
+
+require github.com/google/go-github/v68 v68.0.0
+
+require (
+	github.com/yuin/goldmark v1.7.13
+	go.etcd.io/bbolt v1.3.11 // pinned for now
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/google/go-querystring v1.1.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+)
@@ -0,0 +1,180 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Reference is a container image reference, normalized so that images on
+// Docker Hub have docker.io as Registry and official images are under library/.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// Name returns the image name without tag, like docker.io/library/nginx.
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// Usage is a reference to a container image in a docker-compose file or
+// Kubernetes/Kustomize manifest.
+type Usage struct {
+	Reference
+	Path string
+}
+
+// Find returns the Usages of tagged container images in the YAML files under root,
+// as image keys or Kustomize images entries. Paths of the Usages are relative to root.
+func Find(root string) ([]Usage, error) {
+	var usages []Usage
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yml" && ext != ".yaml" {
+			return nil
+		}
+
+		found, err := parseFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		for i := range found {
+			found[i].Path = filepath.ToSlash(rel)
+		}
+		usages = append(usages, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+	return usages, nil
+}
+
+func parseFile(path string) ([]Usage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var usages []Usage
+	decoder := yaml.NewDecoder(f)
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		walk(&doc, &usages)
+	}
+	return usages, nil
+}
+
+// walk collects the values of all the image keys in the YAML tree, and the
+// entries of Kustomize images lists.
+func walk(node *yaml.Node, usages *[]Usage) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			switch {
+			case key.Value == "image" && value.Kind == yaml.ScalarNode:
+				if ref, ok := ParseReference(value.Value); ok {
+					*usages = append(*usages, Usage{Reference: ref})
+				}
+			case key.Value == "images" && value.Kind == yaml.SequenceNode:
+				for _, entry := range value.Content {
+					if ref, ok := kustomizeImage(entry); ok {
+						*usages = append(*usages, Usage{Reference: ref})
+					}
+				}
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		walk(child, usages)
+	}
+}
+
+// kustomizeImage parses an entry of the images list of a kustomization file,
+// like {name: nginx, newName: my-registry/nginx, newTag: 1.27.0}.
+func kustomizeImage(node *yaml.Node) (Reference, bool) {
+	var entry struct {
+		Name    string `yaml:"name"`
+		NewName string `yaml:"newName"`
+		NewTag  string `yaml:"newTag"`
+	}
+	if err := node.Decode(&entry); err != nil || entry.NewTag == "" {
+		return Reference{}, false
+	}
+
+	name := entry.Name
+	if entry.NewName != "" {
+		name = entry.NewName
+	}
+	return ParseReference(name + ":" + entry.NewTag)
+}
+
+// ParseReference parses an image reference like nginx:1.27, ghcr.io/owner/app:v1.2.0
+// or registry.example.com:5000/app:1.0@sha256:.... References without a tag, with the
+// latest tag or with variables to be interpolated are skipped, as there's no version
+// to compare.
+func ParseReference(s string) (Reference, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "${}") {
+		return Reference{}, false
+	}
+
+	s, _, _ = strings.Cut(s, "@")
+	name, tag := s, ""
+	if i := strings.LastIndex(s, ":"); i > strings.LastIndex(s, "/") {
+		name, tag = s[:i], s[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		return Reference{}, false
+	}
+
+	registry, repository := "docker.io", name
+	if first, rest, ok := strings.Cut(name, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, repository = first, rest
+	}
+	if registry == "index.docker.io" || registry == "registry-1.docker.io" {
+		registry = "docker.io"
+	}
+	if registry == "docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	return Reference{
+		Registry:   registry,
+		Repository: strings.ToLower(repository),
+		Tag:        tag,
+	}, true
+}
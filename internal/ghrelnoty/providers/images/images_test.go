@@ -0,0 +1,50 @@
+package images
+
+import (
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	usages, err := Find("testdata/manifests")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Usage{
+		{Reference{"docker.io", "library/nginx", "1.27.0"}, "docker-compose.yaml"},
+		{Reference{"ghcr.io", "davquar/ghrelnoty", "v0.3.0"}, "docker-compose.yaml"},
+		{Reference{"registry.example.com:5000", "tools/migrate", "2.1"}, "k8s/deployment.yaml"},
+		{Reference{"docker.io", "library/nginx", "1.26.2"}, "k8s/deployment.yaml"},
+		{Reference{"docker.io", "library/postgres", "16.4"}, "k8s/kustomization.yaml"},
+	}
+
+	if len(usages) != len(expected) {
+		t.Fatalf("expected %d usages, got %d: %v", len(expected), len(usages), usages)
+	}
+	for i := range expected {
+		if usages[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], usages[i])
+		}
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		ref  string
+		name string
+		ok   bool
+	}{
+		{"grafana/grafana:11.2.0", "docker.io/grafana/grafana", true},
+		{"localhost/app:1.0", "localhost/app", true},
+		{"quay.io/prometheus/node-exporter:v1.8.2", "quay.io/prometheus/node-exporter", true},
+		{"nginx:latest", "", false},
+		{"nginx@sha256:4c0e1f5a", "", false},
+	}
+
+	for _, tt := range tests {
+		ref, ok := ParseReference(tt.ref)
+		if ok != tt.ok || (ok && ref.Name() != tt.name) {
+			t.Errorf("%s: expected %s (%v), got %s (%v)", tt.ref, tt.name, tt.ok, ref.Name(), ok)
+		}
+	}
+}
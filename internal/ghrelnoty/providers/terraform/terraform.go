@@ -0,0 +1,103 @@
+package terraform
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LockFile is the name of the dependency lock file of Terraform and OpenTofu.
+const LockFile = ".terraform.lock.hcl"
+
+// Lock is a provider locked to a version in a dependency lock file. Provider is
+// the full source address, like registry.terraform.io/hashicorp/aws.
+type Lock struct {
+	Provider string
+	Version  string
+	Path     string
+}
+
+// Find returns the Locks of the dependency lock files under root.
+// Paths of the Locks are relative to root.
+func Find(root string) ([]Lock, error) {
+	var locks []Lock
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != LockFile {
+			return nil
+		}
+
+		found, err := Parse(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		for i := range found {
+			found[i].Path = filepath.ToSlash(rel)
+		}
+		locks = append(locks, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+	return locks, nil
+}
+
+var (
+	providerRegex = regexp.MustCompile(`^provider\s+"([^"]+)"\s*\{`)
+	versionRegex  = regexp.MustCompile(`^version\s*=\s*"([^"]+)"`)
+)
+
+// Parse returns the Locks of the given dependency lock file. The file is generated
+// by Terraform, so only its own formatting of provider blocks is understood.
+func Parse(path string) ([]Lock, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var (
+		locks    []Lock
+		provider string
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := providerRegex.FindStringSubmatch(line); match != nil {
+			provider = match[1]
+			continue
+		}
+		if match := versionRegex.FindStringSubmatch(line); match != nil && provider != "" {
+			locks = append(locks, Lock{
+				Provider: provider,
+				Version:  match[1],
+				Path:     path,
+			})
+			provider = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return locks, nil
+}
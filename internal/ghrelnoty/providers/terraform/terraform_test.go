@@ -0,0 +1,28 @@
+package terraform
+
+import (
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	locks, err := Find("testdata/infra")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Lock{
+		{Provider: "registry.opentofu.org/cloudflare/cloudflare", Version: "4.20.0", Path: "dns/.terraform.lock.hcl"},
+		{Provider: "registry.terraform.io/hashicorp/aws", Version: "5.40.0", Path: "dns/.terraform.lock.hcl"},
+		{Provider: "registry.terraform.io/hashicorp/aws", Version: "5.31.0", Path: "network/.terraform.lock.hcl"},
+		{Provider: "registry.terraform.io/hashicorp/random", Version: "3.6.0", Path: "network/.terraform.lock.hcl"},
+	}
+
+	if len(locks) != len(expected) {
+		t.Fatalf("expected %d locks, got %d: %v", len(expected), len(locks), locks)
+	}
+	for i := range expected {
+		if locks[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], locks[i])
+		}
+	}
+}
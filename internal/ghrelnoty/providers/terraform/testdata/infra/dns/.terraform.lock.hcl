@@ -0,0 +1,17 @@
+# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.opentofu.org/cloudflare/cloudflare" {
+  version     = "4.20.0"
+  constraints = ">= 4.0.0"
+  hashes = [
+    "h1:2Xlr8oLz6zdJbhHqUdGqmEbCLAnjLWEqrHG4LbE2EcY=",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.40.0"
+  hashes = [
+    "h1:ltxyuBWIy9cq0kIKDJH1jeWJy/y7XJLjS4QrsQK4plA=",
+  ]
+}
@@ -0,0 +1,138 @@
+package workflows
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Usage is a reference to a GitHub Action in a workflow or composite action file.
+// Ref is as written after @, while Comment is the trailing comment of the line,
+// which usually holds the version of SHA-pinned references.
+type Usage struct {
+	Repo    string
+	Path    string
+	Ref     string
+	Comment string
+}
+
+var shaRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// IsPinned returns true if the Usage references a full commit SHA.
+func (u Usage) IsPinned() bool {
+	return shaRegex.MatchString(u.Ref)
+}
+
+// Find returns the Usages of GitHub Actions in the workflows (.github/workflows/*.yml)
+// and composite actions (action.yml, anywhere) of the given repository checkout.
+// Paths of the Usages are relative to root.
+func Find(root string) ([]Usage, error) {
+	var usages []Usage
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isWorkflow(root, path) && !isAction(path) {
+			return nil
+		}
+
+		found, err := parseFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		for i := range found {
+			found[i].Path = filepath.ToSlash(rel)
+		}
+		usages = append(usages, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+	return usages, nil
+}
+
+func isWorkflow(root string, path string) bool {
+	ext := filepath.Ext(path)
+	return (ext == ".yml" || ext == ".yaml") &&
+		filepath.Dir(path) == filepath.Join(root, ".github", "workflows")
+}
+
+func isAction(path string) bool {
+	name := filepath.Base(path)
+	return name == "action.yml" || name == "action.yaml"
+}
+
+func parseFile(path string) ([]Usage, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	var usages []Usage
+	walk(&root, &usages)
+	return usages, nil
+}
+
+// walk collects the values of all the uses keys in the YAML tree.
+func walk(node *yaml.Node, usages *[]Usage) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "uses" && value.Kind == yaml.ScalarNode {
+				if u, ok := parseUses(value.Value); ok {
+					u.Comment = strings.TrimSpace(strings.TrimPrefix(value.LineComment, "#"))
+					*usages = append(*usages, u)
+				}
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		walk(child, usages)
+	}
+}
+
+// parseUses parses references like owner/repo@ref and owner/repo/path@ref,
+// skipping local actions and Docker images.
+func parseUses(uses string) (Usage, bool) {
+	if strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "docker://") {
+		return Usage{}, false
+	}
+
+	action, ref, ok := strings.Cut(uses, "@")
+	if !ok || ref == "" {
+		return Usage{}, false
+	}
+
+	parts := strings.Split(action, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return Usage{}, false
+	}
+
+	return Usage{
+		Repo: parts[0] + "/" + parts[1],
+		Ref:  ref,
+	}, true
+}
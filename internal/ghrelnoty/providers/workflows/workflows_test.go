@@ -0,0 +1,32 @@
+package workflows
+
+import (
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	usages, err := Find("testdata/checkout")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Usage{
+		{Repo: "actions/checkout", Path: ".github/workflows/ci.yaml", Ref: "v4"},
+		{Repo: "actions/setup-go", Path: ".github/workflows/ci.yaml", Ref: "0aaccfd150d50ccaeb58ebd88d36e91967a5f35b", Comment: "v5.4.0"},
+		{Repo: "davquar/workflows", Path: ".github/workflows/ci.yaml", Ref: "v1.2.0"},
+		{Repo: "actions/cache", Path: "actions/setup/action.yml", Ref: "v3"},
+	}
+
+	if len(usages) != len(expected) {
+		t.Fatalf("expected %d usages, got %d: %v", len(expected), len(usages), usages)
+	}
+	for i := range expected {
+		if usages[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], usages[i])
+		}
+	}
+
+	if !usages[1].IsPinned() || usages[0].IsPinned() {
+		t.Fatal("expected only the SHA reference to be pinned")
+	}
+}
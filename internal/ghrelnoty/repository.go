@@ -3,30 +3,39 @@ package ghrelnoty
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/google/go-github/v68/github"
 	"it.davquar/gitrelnoty/internal/metrics"
 	"it.davquar/gitrelnoty/pkg/release"
 )
 
+// releasesPerPage is the number of releases requested to GitHub for each repository.
+const releasesPerPage = 30
+
 type GitHubRepository struct {
 	RepositoryConfig
+	Client *github.Client
 }
 
 func (r GitHubRepository) Config() RepositoryConfig {
 	return r.RepositoryConfig
 }
 
-// GetLatestRelease gets the latest Release for the repository and the current rate limits.
-func (r GitHubRepository) GetLatestRelease(ctx context.Context) (release.Release, RateLimitData, error) {
-	client := github.NewClient(nil)
-
+// GetReleases gets the published Releases for the repository, newest first, and the
+// current rate limits. Drafts are skipped, and prereleases are filtered according to
+// RepositoryConfig.Prereleases.
+func (r GitHubRepository) GetReleases(ctx context.Context) ([]release.Release, RateLimitData, error) {
 	author, repo := r.SeparateName()
-	repoRelease, resp, err := client.Repositories.GetLatestRelease(ctx, author, repo)
+	repoReleases, resp, err := r.listReleases(ctx, author, repo)
+
+	if resp == nil {
+		return nil, RateLimitData{}, fmt.Errorf("%s: %w", r.Name, err)
+	}
 
 	rateLimitData, errr := makeRateLimitData(resp.Header)
 	if errr != nil {
-		return release.Release{}, rateLimitData, fmt.Errorf("can't get rate limit data: %w", err)
+		return nil, rateLimitData, fmt.Errorf("can't get rate limit data: %w", errr)
 	}
 
 	metrics.SetRateLimitValue(float64(rateLimitData.Limit))
@@ -34,19 +43,122 @@ func (r GitHubRepository) GetLatestRelease(ctx context.Context) (release.Release
 
 	rateLimitErr := isRateLimited(err)
 	if rateLimitErr != nil {
-		return release.Release{}, rateLimitData, rateLimitErr
+		return nil, rateLimitData, rateLimitErr
 	}
 
 	if err != nil {
-		return release.Release{}, rateLimitData, fmt.Errorf("%s: %w", r.Name, err)
+		return nil, rateLimitData, fmt.Errorf("%s: %w", r.Name, err)
 	}
 
-	release := release.Release{
-		Project:     repo,
-		Author:      author,
-		Version:     repoRelease.GetName(),
-		Description: repoRelease.GetBody(),
-		URL:         repoRelease.GetHTMLURL(),
+	var commits map[string]string
+	if r.DetectMutations {
+		commits, err = tagCommits(ctx, r.Client, author, repo)
+		if err != nil {
+			return nil, rateLimitData, fmt.Errorf("%s: %w", r.Name, err)
+		}
+	}
+
+	releases := make([]release.Release, 0, len(repoReleases))
+	for _, repoRelease := range repoReleases {
+		if repoRelease.GetDraft() || !r.WantsRelease(repoRelease.GetPrerelease()) {
+			continue
+		}
+
+		version, err := r.Extract(repoRelease.GetTagName(), repoRelease.GetName())
+		if err != nil {
+			return nil, rateLimitData, fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		releases = append(releases, release.Release{
+			Kind:        release.KindNew,
+			Project:     repo,
+			Author:      author,
+			Version:     version,
+			Tag:         repoRelease.GetTagName(),
+			Name:        repoRelease.GetName(),
+			Commit:      commits[repoRelease.GetTagName()],
+			Description: repoRelease.GetBody(),
+			URL:         repoRelease.GetHTMLURL(),
+			PublishedAt: repoRelease.GetPublishedAt().Time,
+			Prerelease:  repoRelease.GetPrerelease(),
+			Assets:      repoRelease.assets(),
+		})
+	}
+	return releases, rateLimitData, nil
+}
+
+// tagCommits returns the commit SHAs of the latest tags of the given repository, by tag name.
+func tagCommits(ctx context.Context, client *github.Client, author string, repo string) (map[string]string, error) {
+	tags, _, err := client.Repositories.ListTags(ctx, author, repo, &github.ListOptions{PerPage: 100})
+	if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+		return nil, rateLimitErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+
+	commits := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		commits[tag.GetName()] = tag.GetCommit().GetSHA()
+	}
+	return commits, nil
+}
+
+// gitHubRelease extends github.RepositoryRelease with the asset digests,
+// which are not exposed by go-github.
+type gitHubRelease struct {
+	github.RepositoryRelease
+	Assets []*gitHubAsset `json:"assets,omitempty"`
+}
+
+type gitHubAsset struct {
+	github.ReleaseAsset
+	Digest *string `json:"digest,omitempty"`
+}
+
+// listReleases lists the latest releases of the given repository, like
+// github.RepositoriesService.ListReleases, also decoding asset digests.
+func (r GitHubRepository) listReleases(ctx context.Context, author string, repo string) ([]*gitHubRelease, *github.Response, error) {
+	u := fmt.Sprintf("repos/%s/%s/releases?per_page=%d", author, repo, releasesPerPage)
+	req, err := r.Client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var releases []*gitHubRelease
+	resp, err := r.Client.Do(ctx, req, &releases)
+	if err != nil {
+		return nil, resp, err
+	}
+	return releases, resp, nil
+}
+
+func (g *gitHubRelease) assets() []release.Asset {
+	assets := make([]release.Asset, 0, len(g.Assets))
+	for _, a := range g.Assets {
+		assets = append(assets, release.Asset{
+			Name:        a.GetName(),
+			Size:        int64(a.GetSize()),
+			ContentType: a.GetContentType(),
+			URL:         a.GetBrowserDownloadURL(),
+			Digest:      a.GetDigest(),
+		})
+	}
+	return assets
+}
+
+func (a *gitHubAsset) GetDigest() string {
+	if a == nil || a.Digest == nil {
+		return ""
+	}
+	return *a.Digest
+}
+
+// newGitHubClient returns a GitHub client, authenticated if the given token is not empty.
+func newGitHubClient(token string) *github.Client {
+	client := github.NewClient(nil)
+	if token != "" {
+		return client.WithAuthToken(token)
 	}
-	return release, rateLimitData, nil
+	return client
 }
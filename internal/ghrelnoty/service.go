@@ -6,37 +6,61 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/google/go-github/v68/github"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
 	smtpd "it.davquar/gitrelnoty/internal/ghrelnoty/destinations/smtp"
 	"it.davquar/gitrelnoty/internal/metrics"
 	"it.davquar/gitrelnoty/internal/store"
 	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
 )
 
+// defaultMaxReleases is the default maximum number of releases notified
+// for a repository at each check.
+const defaultMaxReleases = 5
+
 // Service holds the app's configuration and an instance of the KV store
 // in which release data is saved for each repository.
 type Service struct {
 	Config    Config
 	Releasers []Releaser
+	Providers []Provider
 	Notifiers map[string]Notifier
 	Store     store.Store
+	GitHub    *github.Client
+
+	locks   *repoLocks
+	watched *watched
 }
 
 // Notifier is implemented by notification system (Destination)
 type Notifier interface {
-	Notify(release release.Release) error
+	Notify(notification destinations.Notification) error
 }
 
 type Releaser interface {
-	GetLatestRelease(context.Context) (release.Release, RateLimitData, error)
+	GetReleases(context.Context) ([]release.Release, RateLimitData, error)
 	Config() RepositoryConfig
 }
 
+// Provider is implemented by dynamic sources of repositories to watch.
+type Provider interface {
+	Repositories(context.Context) ([]RepositoryConfig, error)
+	Config() ProviderConfig
+}
+
 // New initializes logging, opens the database and returns a new Service.
 func New(config Config) (Service, error) {
 	s := Service{
-		Config: config,
+		Config:  config,
+		GitHub:  newGitHubClient(config.GitHubToken),
+		locks:   &repoLocks{},
+		watched: &watched{},
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
@@ -56,6 +80,11 @@ func New(config Config) (Service, error) {
 		return Service{}, fmt.Errorf("init releasers: %w", err)
 	}
 
+	err = s.initProviders()
+	if err != nil {
+		return Service{}, fmt.Errorf("init providers: %w", err)
+	}
+
 	err = s.initNotifiers()
 	if err != nil {
 		return Service{}, fmt.Errorf("init notifiers: %w", err)
@@ -65,13 +94,159 @@ func New(config Config) (Service, error) {
 }
 
 func (s *Service) initReleasers() error {
+	if err := s.Config.Releases.Validate(); err != nil {
+		return err
+	}
+	if err := validateKeywords(s.Config.Keywords); err != nil {
+		return err
+	}
+	for name, group := range s.Config.Groups {
+		if err := group.Releases.Validate(); err != nil {
+			return fmt.Errorf("group %s: %w", name, err)
+		}
+		s.Config.Groups[name] = group
+	}
+
 	s.Releasers = make([]Releaser, 0, len(s.Config.Repositories))
 	for _, repo := range s.Config.Repositories {
-		switch repo.Type {
-		case "github":
-			s.Releasers = append(s.Releasers, GitHubRepository{repo})
+		if repo.Owner != "" {
+			continue
+		}
+		releaser, err := s.newReleaser(repo)
+		if err != nil {
+			return err
+		}
+		s.Releasers = append(s.Releasers, releaser)
+	}
+	return nil
+}
+
+func (s Service) newReleaser(repo RepositoryConfig) (Releaser, error) {
+	switch repo.Prereleases {
+	case "", "exclude", "include", "only":
+	default:
+		return nil, fmt.Errorf("invalid prereleases value for %s: %s", repo.Name, repo.Prereleases)
+	}
+
+	if err := repo.VersionConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if err := repo.DeployedVersion.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if _, ok := s.Config.Groups[repo.Group]; repo.Group != "" && !ok {
+		return nil, fmt.Errorf("unknown group %s for %s", repo.Group, repo.Name)
+	}
+
+	if err := repo.Releases.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if err := repo.Thresholds.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if err := validateTracks(repo.Tracks); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if err := validateKeywords(repo.Keywords); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if err := repo.Email.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", repo.Name, err)
+	}
+
+	if _, err := path.Match(repo.RequireAssets, ""); err != nil {
+		return nil, fmt.Errorf("invalid require_assets pattern for %s: %w", repo.Name, err)
+	}
+
+	switch repo.Type {
+	case "github":
+		return GitHubRepository{repo, s.GitHub}, nil
+	case "goproxy":
+		return GoProxyRepository{repo, httpClient}, nil
+	case "oci":
+		return OCIRepository{repo, registryClient}, nil
+	case "terraform":
+		return TerraformRepository{repo, httpClient}, nil
+	default:
+		return nil, fmt.Errorf("unknown repo type for %s", repo.Name)
+	}
+}
+
+func (s *Service) initProviders() error {
+	providers := s.Config.Providers
+	for _, repo := range s.Config.Repositories {
+		if repo.Owner == "" {
+			continue
+		}
+		if repo.Type != "github" {
+			return fmt.Errorf("unknown repo type for %s", repo.Owner)
+		}
+
+		template := repo
+		template.Name = repo.Owner + "/*"
+		if _, err := s.newReleaser(template); err != nil {
+			return err
+		}
+		providers = append(providers, ProviderConfig{
+			Type:         "github_owner",
+			User:         repo.Owner,
+			OwnerFilters: repo.OwnerFilters,
+			template:     &template,
+		})
+	}
+
+	s.Providers = make([]Provider, 0, len(providers))
+	for _, p := range providers {
+		for _, pattern := range append(p.Include, p.Exclude...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+			}
+		}
+		if err := p.OwnerFilters.Validate(); err != nil {
+			return err
+		}
+		if _, ok := s.Config.Groups[p.Group]; p.Group != "" && !ok {
+			return fmt.Errorf("unknown group %s for %s provider", p.Group, p.Type)
+		}
+		if err := p.Releases.Validate(); err != nil {
+			return err
+		}
+		if err := p.Email.Validate(); err != nil {
+			return err
+		}
+
+		switch p.Type {
+		case "github_stars", "github_owner":
+			if p.User == "" {
+				return fmt.Errorf("%s provider needs a user", p.Type)
+			}
+		default:
+			if len(p.Paths) == 0 {
+				return fmt.Errorf("%s provider needs paths", p.Type)
+			}
+		}
+
+		switch p.Type {
+		case "github_stars":
+			s.Providers = append(s.Providers, GitHubStarsProvider{p, s.GitHub})
+		case "github_owner":
+			s.Providers = append(s.Providers, GitHubOwnerProvider{p, s.GitHub, &sync.Map{}})
+		case "workflows":
+			s.Providers = append(s.Providers, WorkflowsProvider{p, s.GitHub, &sync.Map{}})
+		case "gomod":
+			s.Providers = append(s.Providers, GoModProvider{p})
+		case "images":
+			s.Providers = append(s.Providers, ImagesProvider{p, registryClient, &sync.Map{}})
+		case "terraform":
+			s.Providers = append(s.Providers, TerraformProvider{p})
 		default:
-			return fmt.Errorf("unknown repo type for %s", repo.Name)
+			return fmt.Errorf("unknown provider type %s", p.Type)
 		}
 	}
 	return nil
@@ -86,6 +261,9 @@ func (s *Service) initNotifiers() error {
 			if !ok {
 				return fmt.Errorf("assert %s of type smtp", name)
 			}
+			if err := dstcfg.Init(); err != nil {
+				return fmt.Errorf("templates of %s: %w", name, err)
+			}
 			s.Notifiers[name] = dstcfg
 		default:
 			return fmt.Errorf("unknown type for %s", name)
@@ -110,10 +288,14 @@ func (s Service) WorkLoop() {
 // - Notify in case of a new release.
 func (s Service) Work(c chan (error)) {
 	defer close(c)
-	for _, repo := range s.Releasers {
+	s.pruneDeliveries()
+
+	releasers := s.releasers(context.Background())
+	s.watched.set(releasers)
+	for _, repo := range releasers {
 		time.Sleep(s.Config.SleepBetween)
 		ctx := context.Background()
-		release, rateLimitData, err := repo.GetLatestRelease(ctx)
+		releases, rateLimitData, err := repo.GetReleases(ctx)
 
 		if rateLimitData.IsAtRisk() {
 			metrics.RateLimitRisk()
@@ -121,47 +303,504 @@ func (s Service) Work(c chan (error)) {
 			time.Sleep(30 * time.Minute)
 		}
 
+		name := repo.Config().Name
 		if err != nil {
 			metrics.CannotGetRelease()
-			slog.ErrorContext(ctx, "can't get latest release", slog.Any("err", err))
+			slog.ErrorContext(ctx, "can't get releases", slog.Any("err", err))
+			if s.waitRateLimit(ctx, err, rateLimitData) {
+				continue
+			}
+		} else {
+			unlock := s.locks.lock(name)
+			err = s.process(ctx, repo.Config(), releases)
+			unlock()
+			if err != nil {
+				slog.ErrorContext(ctx, "can't process releases", slog.String("repo", name), slog.Any("err", err))
+			}
+
+			err = s.checkAdvisories(ctx, repo)
+			if err != nil {
+				slog.ErrorContext(ctx, "can't check advisories", slog.String("repo", name), slog.Any("err", err))
+				s.waitRateLimit(ctx, err, rateLimitData)
+			}
+		}
+
+		// The lifecycle is checked even if the releases can't be listed, as it
+		// happens to deleted, renamed or transferred repositories.
+		err = s.checkLifecycle(ctx, repo)
+		if err != nil {
+			slog.ErrorContext(ctx, "can't check lifecycle", slog.String("repo", name), slog.Any("err", err))
+			s.waitRateLimit(ctx, err, rateLimitData)
+		}
+	}
+}
+
+// waitRateLimit pauses until the rate limit resets, according to the given data,
+// if the given error is a RateLimitError, returning true if so.
+func (s Service) waitRateLimit(ctx context.Context, err error, rateLimitData RateLimitData) bool {
+	var errRateLimited *RateLimitError
+	if !errors.As(err, &errRateLimited) {
+		return false
+	}
+	metrics.RateLimited()
+	slog.ErrorContext(ctx, "hit rate limit: resuming activities at", slog.Any("time", rateLimitData.ResetAt))
+	time.Sleep(time.Until(rateLimitData.ResetAt))
+	return true
+}
 
-			var errRateLimited *RateLimitError
-			if errors.As(err, &errRateLimited) {
-				metrics.RateLimited()
-				slog.ErrorContext(ctx, "hit rate limit: resuming activities at", slog.Any("time", rateLimitData.ResetAt))
-				time.Sleep(time.Until(rateLimitData.ResetAt))
+// process compares the given releases (newest first) with the stored record of the
+// repository, stores the new record and notifies the new releases.
+// Releases that are still waiting for their required assets are recorded as pending,
+// and logged once ready.
+// With tracks, the releases of each track are processed on their own, under a key
+// made of the repository name and the track name.
+func (s Service) process(ctx context.Context, repo RepositoryConfig, releases []release.Release) error {
+	if len(repo.Tracks) > 0 {
+		var errs []error
+		for _, t := range repo.Tracks {
+			errs = append(errs, s.process(ctx, repo.ForTrack(t), t.Filter(releases)))
+		}
+		return errors.Join(errs...)
+	}
+
+	if len(releases) == 0 {
+		slog.Debug("no releases", slog.String("repo", repo.Name), slog.String("track", repo.Track))
+		return nil
+	}
+
+	key, err := s.Store.ResolveKey(repo.Name)
+	if err != nil {
+		metrics.DBError()
+		slog.ErrorContext(ctx, "can't read from db", slog.String("repo", repo.Name), slog.Any("err", err))
+		return err
+	}
+	if repo.Track != "" {
+		key += "#" + repo.Track
+	}
+
+	current, err := s.Store.Get(key)
+	if err != nil {
+		metrics.DBError()
+		slog.ErrorContext(ctx, "can't read from db", slog.String("repo", repo.Name), slog.Any("err", err))
+		return err
+	}
+
+	var errs []error
+	if repo.DetectMutations {
+		for _, f := range announced(current) {
+			if mutations := detectMutations(releases, f); len(mutations) > 0 {
+				errs = append(errs, s.notifyMutations(repo, releases, f, mutations))
 			}
+		}
+	}
+
+	kept, suppressed := s.suppress(repo, releases, current)
+	ready, pending := splitPending(kept, current, repo.RequireAssets)
+	ready, young, detected, superseded := holdYoung(ready, current, repo.MinAge, time.Now())
+	record := current
+	record.Legacy = false
+	record.Pending = append(pending, young...)
+	record.Detected = detected
+	record.Suppressed = suppressed
+	if len(ready) > 0 {
+		latest := baseline(ready, current)
+		record.Version = latest.Version
+		record.Tag = latest.Tag
+		record.PublishedAt = latest.PublishedAt
+		record.Commit = latest.Commit
+		record.Assets = assetDigests(latest.Assets)
+	}
+	if len(pending) > 0 {
+		slog.Debug("releases waiting for assets", slog.String("repo", repo.Name), slog.Any("tags", pending))
+	}
+	for _, r := range noLongerPending(ready, current) {
+		attrs := []any{slog.String("repo", repo.Name), slog.String("release", r.Version)}
+		if !r.PublishedAt.IsZero() {
+			attrs = append(attrs, slog.Duration("waited", time.Since(r.PublishedAt).Round(time.Second)))
+		}
+		slog.Info("pending release ready", attrs...)
+	}
+	if len(young) > 0 {
+		slog.Debug("releases waiting to be old enough", slog.String("repo", repo.Name), slog.Any("tags", young))
+	}
+
+	deployed, err := repo.DeployedVersion.Resolve()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", repo.Name, err))
+	}
+	if deployed != "" && len(ready) > 0 {
+		errs = append(errs, s.reportBehind(repo, key, deployed, latestRelease(ready), ready))
+	}
+
+	// Releases superseded while held for their minimum age are skipped.
+	limit := s.maxReleases()
+	if superseded {
+		limit = 1
+	}
+	var notified []release.Release
+	if record.Version != current.Version {
+		notified = notifiable(repo, newReleases(ready, current, limit), current.Version)
+	}
+	if repo.DetectMutations {
+		record.Watched = fingerprints(releases, current, notified, record.Tag)
+	}
+
+	changed, err := s.Store.CompareAndSet(key, record)
+	if err != nil {
+		metrics.DBError()
+		slog.ErrorContext(ctx, "can't store in db", slog.String("repo", repo.Name), slog.Any("err", err))
+		return errors.Join(append(errs, err)...)
+	}
+
+	slog.Debug("got data", slog.String("repo", repo.Name), slog.String("release", record.Version), slog.Bool("changed", changed))
+
+	errs = append(errs, s.reportBackports(repo, key, backports(ready, current)))
+
+	if !changed {
+		return errors.Join(errs...)
+	}
+
+	for _, r := range notified {
+		r.References = repo.ReferencesFor(r, ready)
+		if behind, ok := versionsBehind(deployed, r, ready); deployed != "" && ok {
+			r.Behind = &behind
+		}
+		errs = append(errs, s.notify(repo, r))
+	}
+	return errors.Join(errs...)
+}
+
+// notifiable counts the given new releases (lowest first), and returns the ones that
+// meet the thresholds of the repository, given the version before them.
+func notifiable(repo RepositoryConfig, found []release.Release, previous string) []release.Release {
+	var notifiable []release.Release
+	for _, r := range found {
+		metrics.NewReleaseFound()
+		notifies := repo.Notifies(r, previous)
+		previous = r.Version
+		if !notifies {
+			slog.Debug("release below thresholds", slog.String("repo", repo.Name), slog.String("release", r.Version))
 			continue
 		}
+		notifiable = append(notifiable, r)
+	}
+	return notifiable
+}
 
-		changed, err := s.Store.CompareAndSet(repo.Config().Name, release.Version)
+// reportBackports logs and counts the backports not seen before, notifying them
+// if RepositoryConfig.Backports is set, and records them as seen once notified.
+func (s Service) reportBackports(repo RepositoryConfig, key string, found []release.Release) error {
+	var errs []error
+	for _, r := range found {
+		seenKey := key + "/" + r.Tag
+		seen, err := s.Store.Seen(store.BackportsBucket, seenKey)
 		if err != nil {
 			metrics.DBError()
-			slog.ErrorContext(ctx, "can't store in db", slog.String("repo", repo.Config().Name), slog.Any("err", err))
-			c <- err
+			errs = append(errs, fmt.Errorf("read backport of %s: %w", repo.Name, err))
+			continue
+		}
+		if seen {
+			continue
 		}
 
-		slog.Debug("got data", slog.String("repo", repo.Config().Name), slog.String("release", release.Version), slog.Bool("changed", changed))
+		metrics.BackportFound()
+		slog.Info("backport found", slog.String("repo", repo.Name), slog.String("release", r.Version))
+		if repo.Backports {
+			r.Kind = release.KindBackport
+			r.References = repo.ReferencesFor(r, found)
+			if err := s.notify(repo, r); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
 
-		if changed {
-			metrics.NewReleaseFound()
-			notifier, ok := s.Notifiers[repo.Config().Destination]
-			if !ok {
-				metrics.NotificationError()
-				slog.Error("notifier not found", slog.String("destination", repo.Config().Destination))
-				c <- errors.New("notifier not found")
+		if _, err := s.Store.MarkSeen(store.BackportsBucket, seenKey); err != nil {
+			metrics.DBError()
+			errs = append(errs, fmt.Errorf("store backport of %s: %w", repo.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifyMutations notifies the changes to the announced release with the given
+// fingerprint, with the latest data of the release, if it still exists.
+func (s Service) notifyMutations(repo RepositoryConfig, releases []release.Release, announced store.Fingerprint, mutations []string) error {
+	metrics.ReleaseMutated()
+	slog.Warn("release mutated", slog.String("repo", repo.Name), slog.String("tag", announced.Tag), slog.Any("mutations", mutations))
+
+	author, project := repo.SeparateName()
+	r := release.Release{
+		Project: project,
+		Author:  author,
+		Version: announced.Version,
+		Tag:     announced.Tag,
+	}
+	if i := slices.IndexFunc(releases, func(r release.Release) bool { return r.Tag == announced.Tag }); i >= 0 {
+		r = releases[i]
+	}
+	r.Kind = release.KindMutated
+	r.Changes = mutations
+
+	return s.notify(repo, r)
+}
+
+// splitPending returns the releases (given newest first) that are ready to be notified,
+// and the tags of the ones newer than the current record that don't have an asset
+// matching the required pattern yet. Without a pattern, all the releases are ready,
+// and so are the ones already known.
+func splitPending(releases []release.Release, current store.Record, requireAssets string) ([]release.Release, []string) {
+	if requireAssets == "" {
+		return releases, nil
+	}
+
+	var (
+		ready        []release.Release
+		pending      []string
+		listedBefore = true
+	)
+	for _, r := range releases {
+		newer := current.Version == ""
+		if !newer && isCurrent(r, current) {
+			listedBefore = false
+		} else if !newer {
+			newer = isNewer(r, current, listedBefore)
+		}
+
+		if !newer || r.HasAsset(requireAssets) {
+			ready = append(ready, r)
+		} else {
+			pending = append(pending, r.Tag)
+		}
+	}
+	return ready, pending
+}
+
+// noLongerPending returns the given ready releases that were pending in the current
+// record.
+func noLongerPending(ready []release.Release, current store.Record) []release.Release {
+	var found []release.Release
+	for _, r := range ready {
+		if slices.Contains(current.Pending, r.Tag) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// notify sends the given release to the destination of the given repository,
+// unless it's muted.
+func (s Service) notify(repo RepositoryConfig, r release.Release) error {
+	if s.muted(repo, r) {
+		return nil
+	}
+
+	r = s.prioritize(repo, r)
+	r.Track = repo.Track
+	destination := repo.DestinationFor(r)
+	notifier, ok := s.Notifiers[destination]
+	if !ok {
+		metrics.NotificationError()
+		slog.Error("notifier not found", slog.String("destination", destination))
+		return errors.New("notifier not found")
+	}
+
+	err := notifier.Notify(destinations.Notification{
+		Release: r,
+		Repository: destinations.Repository{
+			Type:        repo.Type,
+			Name:        repo.Name,
+			Destination: destination,
+			Track:       repo.Track,
+			Email:       repo.Email,
+		},
+	})
+	// The email was sent to the other recipients, so the release is not notified again.
+	var rejected *smtpd.RejectedRecipientsError
+	if errors.As(err, &rejected) {
+		metrics.NotificationError()
+		slog.Warn("some recipients rejected", slog.String("repo", repo.Name), slog.String("release", r.Version), slog.Any("err", err))
+		return nil
+	}
+	if err != nil {
+		metrics.NotificationError()
+		slog.Error("cannot notify", slog.Any("err", err))
+		return err
+	}
+	return nil
+}
+
+// maxReleases returns the maximum number of releases to notify for a repository
+// at each check.
+func (s Service) maxReleases() int {
+	if s.Config.MaxReleases > 0 {
+		return s.Config.MaxReleases
+	}
+	return defaultMaxReleases
+}
+
+// newReleases returns the releases (given newest first) newer than the current
+// record, according to isNewer, lowest first and limited to the highest limit.
+// If there is no current record, only the latest release is returned.
+func newReleases(releases []release.Release, current store.Record, limit int) []release.Release {
+	if len(releases) == 0 {
+		return nil
+	}
+	if current.Version == "" {
+		return []release.Release{latestRelease(releases)}
+	}
+
+	var found []release.Release
+	listedBefore := true
+	for _, r := range releases {
+		if isCurrent(r, current) {
+			listedBefore = false
+			continue
+		}
+		if isNewer(r, current, listedBefore) {
+			found = append(found, r)
+		}
+	}
+
+	slices.Reverse(found)
+	sortBySemver(found)
+	if len(found) > limit {
+		found = found[len(found)-limit:]
+	}
+	return found
+}
+
+// backports returns the releases (given newest first) with a version lower than
+// the current record, but published after it, lowest first.
+func backports(releases []release.Release, current store.Record) []release.Release {
+	c, err := semver.Parse(current.Version)
+	if err != nil || current.Legacy || current.PublishedAt.IsZero() {
+		return nil
+	}
+
+	var found []release.Release
+	for _, r := range releases {
+		v, err := semver.Parse(r.Version)
+		if err == nil && v.Compare(c) < 0 && r.PublishedAt.After(current.PublishedAt) {
+			found = append(found, r)
+		}
+	}
+	slices.Reverse(found)
+	sortBySemver(found)
+	return found
+}
+
+// baseline returns the release to store as the current one: the highest new release,
+// the current one if there are no new releases, or the latest one if the current
+// release is not listed anymore, for example because it was deleted.
+func baseline(releases []release.Release, current store.Record) release.Release {
+	if found := newReleases(releases, current, len(releases)); len(found) > 0 {
+		return found[len(found)-1]
+	}
+	if i := slices.IndexFunc(releases, func(r release.Release) bool { return isCurrent(r, current) }); i >= 0 {
+		return releases[i]
+	}
+	return latestRelease(releases)
+}
+
+// latestRelease returns the release with the highest semver version, or the first
+// (most recent) one if none is semver.
+func latestRelease(releases []release.Release) release.Release {
+	latest := releases[0]
+	var highest *semver.Version
+	for _, r := range releases {
+		v, err := semver.Parse(r.Version)
+		if err != nil {
+			continue
+		}
+		if highest == nil || v.Compare(*highest) > 0 {
+			latest, highest = r, &v
+		}
+	}
+	return latest
+}
+
+// sortBySemver sorts the releases by version, lowest first, if they are all semver.
+func sortBySemver(releases []release.Release) {
+	versions := make(map[string]semver.Version, len(releases))
+	for _, r := range releases {
+		v, err := semver.Parse(r.Version)
+		if err != nil {
+			return
+		}
+		versions[r.Version] = v
+	}
+	slices.SortStableFunc(releases, func(a, b release.Release) int {
+		return versions[a.Version].Compare(versions[b.Version])
+	})
+}
+
+// releasers returns the configured Releasers, merged with the ones listed by the
+// Providers at the time of the call. Repositories that are explicitly configured
+// take precedence over the ones coming from Providers, so that their destination is kept.
+func (s Service) releasers(ctx context.Context) []Releaser {
+	if len(s.Providers) == 0 {
+		return s.Releasers
+	}
+
+	releasers := make([]Releaser, 0, len(s.Releasers))
+	seen := make(map[string]bool)
+	for _, r := range s.Releasers {
+		releasers = append(releasers, r)
+		seen[r.Config().Name] = true
+	}
+
+	for _, p := range s.Providers {
+		repos, err := p.Repositories(ctx)
+		if err != nil {
+			metrics.ProviderError()
+			slog.ErrorContext(ctx, "can't list repositories", slog.String("provider", p.Config().Type), slog.Any("err", err))
+			continue
+		}
+
+		for _, repo := range repos {
+			if seen[repo.Name] || !p.Config().Matches(repo.Name) {
 				continue
 			}
 
-			err = notifier.Notify(release)
+			r, err := s.newReleaser(repo)
 			if err != nil {
-				metrics.NotificationError()
-				slog.Error("cannot notify", slog.Any("err", err))
-				c <- err
+				slog.ErrorContext(ctx, "can't watch repository", slog.String("repo", repo.Name), slog.Any("err", err))
 				continue
 			}
+			releasers = append(releasers, r)
+			seen[repo.Name] = true
 		}
 	}
+
+	return releasers
+}
+
+// isNewer returns true if the release is newer than the current record. Versions are
+// compared as semver, so that backports aren't new; if either is not semver, publication
+// times are compared, and if those are unknown too, releases listed before the current
+// one are new.
+func isNewer(r release.Release, current store.Record, listedBefore bool) bool {
+	v, errR := semver.Parse(r.Version)
+	c, errC := semver.Parse(current.Version)
+	if errR == nil && errC == nil && !current.Legacy {
+		return v.Compare(c) > 0
+	}
+	if !r.PublishedAt.IsZero() && !current.PublishedAt.IsZero() {
+		return r.PublishedAt.After(current.PublishedAt)
+	}
+	return listedBefore
+}
+
+// isCurrent returns true if the release is the one described by the record.
+// Legacy records hold the release name instead of the normalized version.
+func isCurrent(r release.Release, current store.Record) bool {
+	if current.Legacy {
+		return r.Name == current.Version || r.Version == current.Version
+	}
+	if current.Tag != "" && r.Tag == current.Tag {
+		return true
+	}
+	return r.Version == current.Version
 }
 
 // Close closes the Service's handles, currently only the database.
@@ -2,46 +2,65 @@ package ghrelnoty
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+	smtpd "it.davquar/gitrelnoty/internal/ghrelnoty/destinations/smtp"
+	"it.davquar/gitrelnoty/internal/store"
 	"it.davquar/gitrelnoty/pkg/release"
 )
 
-type dummyRepoConfig struct {
-	Type        string `yaml:"type"`
-	Name        string `yaml:"name"`
-	Destination string `yaml:"destination"`
-}
-
 type dummyReleaser struct {
-	dummyRepoConfig
+	RepositoryConfig
 }
 
-func (r dummyReleaser) GetLatestRelease(_ context.Context) (release.Release, RateLimitData, error) {
-	return release.Release{
+func (r dummyReleaser) GetReleases(_ context.Context) ([]release.Release, RateLimitData, error) {
+	return []release.Release{{
 		Project:     "name",
 		Author:      "author",
 		Version:     "v1.2.3",
 		Description: "some test description",
 		URL:         "https://github.com/davquar/ghrelnoty/releases/tag/v1.2.3",
-	}, RateLimitData{}, nil
+	}}, RateLimitData{}, nil
 }
 
 func (r dummyReleaser) Config() RepositoryConfig {
-	return RepositoryConfig(r.dummyRepoConfig)
+	return r.RepositoryConfig
 }
 
 type dummyNotifier struct{}
 
-func (d dummyNotifier) Notify(release release.Release) error {
+func (d dummyNotifier) Notify(notification destinations.Notification) error {
 	// nolint (allow printing in the test output)
-	fmt.Println("dummy notification", release)
+	fmt.Println("dummy notification", notification.Release)
 	return nil
 }
 
+// newTestService returns a Service with a temporary database, closed at the end of
+// the test, sending the notifications of each of the given destinations to its own
+// chanNotifier.
+func newTestService(t *testing.T, destinations ...string) (Service, map[string]chanNotifier) {
+	t.Helper()
+	s, err := New(Config{DBPath: filepath.Join(t.TempDir(), "ghrelnoty.db")})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	t.Cleanup(s.Close)
+
+	notifiers := make(map[string]chanNotifier, len(destinations))
+	s.Notifiers = make(map[string]Notifier, len(destinations))
+	for _, d := range destinations {
+		notifiers[d] = make(chanNotifier, 10)
+		s.Notifiers[d] = notifiers[d]
+	}
+	return s, notifiers
+}
+
 func TestWork(t *testing.T) {
 	f, err := os.CreateTemp("", "ghrelnoty-")
 	if err != nil {
@@ -61,7 +80,7 @@ func TestWork(t *testing.T) {
 
 	s.Releasers = []Releaser{
 		dummyReleaser{
-			dummyRepoConfig{
+			RepositoryConfig{
 				Name:        "author/name",
 				Destination: "noop",
 			},
@@ -79,3 +98,228 @@ func TestWork(t *testing.T) {
 		t.Fatalf("%v", err)
 	}
 }
+
+type dummyProvider struct {
+	repos []RepositoryConfig
+}
+
+func (p dummyProvider) Repositories(_ context.Context) ([]RepositoryConfig, error) {
+	return p.repos, nil
+}
+
+func (p dummyProvider) Config() ProviderConfig {
+	return ProviderConfig{Exclude: []string{"excluded/*"}}
+}
+
+func TestReleasersMergesProviders(t *testing.T) {
+	s := Service{
+		Releasers: []Releaser{
+			dummyReleaser{RepositoryConfig{Type: "github", Name: "author/static", Destination: "static"}},
+		},
+		Providers: []Provider{
+			dummyProvider{[]RepositoryConfig{
+				{Type: "github", Name: "author/static", Destination: "stars"},
+				{Type: "github", Name: "author/starred", Destination: "stars"},
+				{Type: "github", Name: "excluded/starred", Destination: "stars"},
+			}},
+		},
+	}
+
+	releasers := s.releasers(context.Background())
+	if len(releasers) != 2 {
+		t.Fatalf("expected 2 releasers, got %d", len(releasers))
+	}
+
+	if dst := releasers[0].Config().Destination; dst != "static" {
+		t.Fatalf("expected configured repository to keep destination static, got %s", dst)
+	}
+
+	if name := releasers[1].Config().Name; name != "author/starred" {
+		t.Fatalf("expected author/starred, got %s", name)
+	}
+}
+
+func TestNewReleases(t *testing.T) {
+	releases := []release.Release{
+		{Version: "1.4.1"},
+		{Version: "1.4.0"},
+		{Version: "1.3.0"},
+		{Version: "1.2.0"},
+	}
+
+	got := newReleases(releases, store.Record{Version: "1.3.0"}, 5)
+	if len(got) != 2 || got[0].Version != "1.4.0" || got[1].Version != "1.4.1" {
+		t.Fatalf("expected [1.4.0 1.4.1], got %v", got)
+	}
+
+	got = newReleases(releases, store.Record{}, 5)
+	if len(got) != 1 || got[0].Version != "1.4.1" {
+		t.Fatalf("expected only the latest release without a current version, got %v", got)
+	}
+
+	got = newReleases(releases, store.Record{Version: "1.0.0"}, 2)
+	if len(got) != 2 || got[0].Version != "1.4.0" || got[1].Version != "1.4.1" {
+		t.Fatalf("expected the newest 2 releases, got %v", got)
+	}
+
+	got = newReleases(releases, store.Record{Version: "1.4.1"}, 5)
+	if len(got) != 0 {
+		t.Fatalf("expected no new releases, got %v", got)
+	}
+}
+
+func TestNewReleasesLegacyRecord(t *testing.T) {
+	releases := []release.Release{
+		{Version: "1.4.1", Tag: "v1.4.1", Name: "Spring Release"},
+		{Version: "1.4.0", Tag: "v1.4.0", Name: "Winter Release"},
+	}
+
+	got := newReleases(releases, store.Record{Version: "Winter Release", Legacy: true}, 5)
+	if len(got) != 1 || got[0].Version != "1.4.1" {
+		t.Fatalf("expected [1.4.1], got %v", got)
+	}
+
+	got = newReleases(releases, store.Record{Version: "v1.4.1", Tag: "v1.4.1"}, 5)
+	if len(got) != 0 {
+		t.Fatalf("expected the tag to identify the current release, got %v", got)
+	}
+}
+
+func TestSplitPending(t *testing.T) {
+	linux := []release.Asset{{Name: "app_linux_amd64.tar.gz"}}
+	releases := []release.Release{
+		{Version: "1.4.1", Tag: "v1.4.1"},
+		{Version: "1.4.0", Tag: "v1.4.0", Assets: linux},
+		{Version: "1.3.0", Tag: "v1.3.0"},
+	}
+
+	ready, pending := splitPending(releases, store.Record{Version: "1.3.0", Tag: "v1.3.0"}, "*linux_amd64.tar.gz")
+	if len(pending) != 1 || pending[0] != "v1.4.1" {
+		t.Fatalf("expected v1.4.1 to be pending, got %v", pending)
+	}
+	if len(ready) != 2 || ready[0].Version != "1.4.0" || ready[1].Version != "1.3.0" {
+		t.Fatalf("expected [1.4.0 1.3.0] to be ready, got %v", ready)
+	}
+
+	ready, pending = splitPending(releases, store.Record{Version: "1.3.0"}, "")
+	if len(pending) != 0 || len(ready) != 3 {
+		t.Fatalf("expected all releases to be ready without a pattern, got %v, %v", ready, pending)
+	}
+
+	releases[0].Assets = linux
+	current := store.Record{Version: "1.4.0", Tag: "v1.4.0", Pending: []string{"v1.4.1"}}
+	ready, _ = splitPending(releases, current, "*linux_amd64.tar.gz")
+	if got := noLongerPending(ready, current); len(got) != 1 || got[0].Tag != "v1.4.1" {
+		t.Fatalf("expected v1.4.1 to be no longer pending, got %v", got)
+	}
+}
+
+func TestNewReleasesSemver(t *testing.T) {
+	t0 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	releases := []release.Release{
+		{Version: "1.9.5", Tag: "v1.9.5", PublishedAt: t0.AddDate(0, 0, 2)},
+		{Version: "2.1.0", Tag: "v2.1.0", PublishedAt: t0.AddDate(0, 0, 1)},
+		{Version: "2.0.0", Tag: "v2.0.0", PublishedAt: t0},
+	}
+
+	got := newReleases(releases, store.Record{Version: "2.0.0", Tag: "v2.0.0", PublishedAt: t0}, 5)
+	if len(got) != 1 || got[0].Version != "2.1.0" {
+		t.Fatalf("expected [2.1.0], got %v", got)
+	}
+
+	current := store.Record{Version: "2.1.0", Tag: "v2.1.0", PublishedAt: t0.AddDate(0, 0, 1)}
+	if got := newReleases(releases, current, 5); len(got) != 0 {
+		t.Fatalf("expected the backport not to be new, got %v", got)
+	}
+	if got := backports(releases, current); len(got) != 1 || got[0].Version != "1.9.5" {
+		t.Fatalf("expected [1.9.5] as backport, got %v", got)
+	}
+	if got := baseline(releases, current); got.Version != "2.1.0" {
+		t.Fatalf("expected baseline 2.1.0, got %s", got.Version)
+	}
+
+	if got := newReleases(releases, store.Record{}, 5); len(got) != 1 || got[0].Version != "2.1.0" {
+		t.Fatalf("expected only the highest release without a current version, got %v", got)
+	}
+}
+
+func TestNewReleasesPublishDateFallback(t *testing.T) {
+	t0 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	releases := []release.Release{
+		{Version: "nightly-0303", Tag: "nightly-0303", PublishedAt: t0.AddDate(0, 0, 2)},
+		{Version: "nightly-0302", Tag: "nightly-0302", PublishedAt: t0.AddDate(0, 0, 1)},
+		{Version: "nightly-0301", Tag: "nightly-0301", PublishedAt: t0},
+	}
+
+	got := newReleases(releases, store.Record{Version: "nightly-0301", Tag: "nightly-0301", PublishedAt: t0}, 5)
+	if len(got) != 2 || got[0].Version != "nightly-0302" || got[1].Version != "nightly-0303" {
+		t.Fatalf("expected [nightly-0302 nightly-0303], got %v", got)
+	}
+
+	// The current release was deleted, the ones published after it are new.
+	deleted := []release.Release{releases[0], releases[2]}
+	got = newReleases(deleted, store.Record{Version: "nightly-0302", Tag: "nightly-0302", PublishedAt: t0.AddDate(0, 0, 1)}, 5)
+	if len(got) != 1 || got[0].Version != "nightly-0303" {
+		t.Fatalf("expected [nightly-0303], got %v", got)
+	}
+}
+
+// rejectingNotifier counts its notifications, which are sent with some of the
+// recipients rejected.
+type rejectingNotifier struct {
+	sent *int
+}
+
+func (n rejectingNotifier) Notify(_ destinations.Notification) error {
+	*n.sent++
+	return &smtpd.RejectedRecipientsError{Rejected: map[string]error{"ops@test.test": errors.New("550 no such user")}}
+}
+
+func TestBackportsPartiallyRejected(t *testing.T) {
+	s, _ := newTestService(t)
+	sent := 0
+	s.Notifiers["email"] = rejectingNotifier{&sent}
+
+	repo := RepositoryConfig{Name: "author/name", Destination: "email", Backports: true}
+	found := []release.Release{{Version: "1.9.5", Tag: "v1.9.5"}}
+	for i := 0; i < 2; i++ {
+		if err := s.reportBackports(repo, "author/name", found); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Fatalf("expected the backport to be sent once, got %d", sent)
+	}
+}
+
+func TestProcessBackports(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+
+	t0 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := RepositoryConfig{Name: "author/name", Destination: "chan", Backports: true}
+	releases := []release.Release{{Version: "2.1.0", Tag: "v2.1.0", PublishedAt: t0}}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-notifications
+
+	releases = append([]release.Release{{Version: "1.9.5", Tag: "v1.9.5", PublishedAt: t0.Add(time.Hour)}}, releases...)
+	for i := 0; i < 2; i++ {
+		if err := s.process(context.Background(), repo, releases); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if r := <-notifications; r.Kind != release.KindBackport || r.Version != "1.9.5" {
+		t.Fatalf("expected backport 1.9.5, got %+v", r)
+	}
+
+	record, err := s.Store.Get("author/name")
+	if err != nil || record.Version != "2.1.0" {
+		t.Fatalf("expected the baseline to stay at 2.1.0, got %+v, %v", record, err)
+	}
+}
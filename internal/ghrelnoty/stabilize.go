@@ -0,0 +1,54 @@
+package ghrelnoty
+
+import (
+	"time"
+
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// holdYoung splits the given releases in the ones ready to be processed and the tags
+// of the newer ones held back until the latest of them is at least minAge old, along
+// with the time each held release was first detected. The age of a release is
+// measured from its publication time or, if unknown, from its first detection.
+// Once ready, superseded is true if releases were held at a previous check and newer
+// ones came in the meantime, so that only the latest of them is notified. The first check of a
+// repository is never held, as it only sets the baseline.
+func holdYoung(releases []release.Release, current store.Record, minAge time.Duration, now time.Time) (ready []release.Release, pending []string, detected map[string]time.Time, superseded bool) {
+	if minAge <= 0 || current.Version == "" {
+		return releases, nil, nil, false
+	}
+
+	newer := newReleases(releases, current, len(releases))
+	if len(newer) == 0 {
+		return releases, nil, nil, false
+	}
+
+	detected = make(map[string]time.Time, len(newer))
+	held := false
+	for _, r := range newer {
+		detected[r.Tag] = now
+		if t, ok := current.Detected[r.Tag]; ok {
+			detected[r.Tag] = t
+			held = true
+		}
+	}
+
+	latest := newer[len(newer)-1]
+	published := latest.PublishedAt
+	if published.IsZero() {
+		published = detected[latest.Tag]
+	}
+	if now.Sub(published) >= minAge {
+		return releases, nil, nil, held && len(newer) > 1
+	}
+
+	for _, r := range releases {
+		if _, ok := detected[r.Tag]; ok {
+			pending = append(pending, r.Tag)
+		} else {
+			ready = append(ready, r)
+		}
+	}
+	return ready, pending, detected, false
+}
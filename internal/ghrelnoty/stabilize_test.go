@@ -0,0 +1,101 @@
+package ghrelnoty
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestHoldYoung(t *testing.T) {
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	current := store.Record{Version: "1.0.0", Tag: "v1.0.0", PublishedAt: now.AddDate(0, -1, 0)}
+	old := release.Release{Version: "1.1.0", Tag: "v1.1.0", PublishedAt: now.Add(-96 * time.Hour)}
+	young := release.Release{Version: "1.1.1", Tag: "v1.1.1", PublishedAt: now.Add(-time.Hour)}
+	unknown := release.Release{Version: "1.2.0", Tag: "v1.2.0"}
+	later := release.Release{Version: "1.3.0", Tag: "v1.3.0", PublishedAt: now.Add(-80 * time.Hour)}
+	base := release.Release{Version: "1.0.0", Tag: "v1.0.0", PublishedAt: current.PublishedAt}
+
+	held := current
+	held.Detected = map[string]time.Time{"v1.1.0": now.Add(-96 * time.Hour)}
+
+	cases := []struct {
+		name       string
+		releases   []release.Release
+		current    store.Record
+		ready      []string
+		pending    []string
+		superseded bool
+	}{
+		{"old enough", []release.Release{old, base}, current, []string{"v1.1.0", "v1.0.0"}, nil, false},
+		{"superseded by a young release", []release.Release{young, old, base}, current, []string{"v1.0.0"}, []string{"v1.1.1", "v1.1.0"}, false},
+		{"first check", []release.Release{young, base}, store.Record{}, []string{"v1.1.1", "v1.0.0"}, nil, false},
+		{"first detected now", []release.Release{unknown, base}, current, []string{"v1.0.0"}, []string{"v1.2.0"}, false},
+		{
+			"detected long ago",
+			[]release.Release{unknown, base},
+			store.Record{Version: "1.0.0", Tag: "v1.0.0", Detected: map[string]time.Time{"v1.2.0": now.Add(-96 * time.Hour)}},
+			[]string{"v1.2.0", "v1.0.0"},
+			nil,
+			false,
+		},
+		{"old enough without holding", []release.Release{later, old, base}, current, []string{"v1.3.0", "v1.1.0", "v1.0.0"}, nil, false},
+		{"superseded while held", []release.Release{later, old, base}, held, []string{"v1.3.0", "v1.1.0", "v1.0.0"}, nil, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ready, pending, _, superseded := holdYoung(c.releases, c.current, 72*time.Hour, now)
+			var tags []string
+			for _, r := range ready {
+				tags = append(tags, r.Tag)
+			}
+			if !slices.Equal(tags, c.ready) || !slices.Equal(pending, c.pending) || superseded != c.superseded {
+				t.Fatalf("expected ready %v, pending %v and superseded %t, got %v, %v and %t", c.ready, c.pending, c.superseded, tags, pending, superseded)
+			}
+		})
+	}
+}
+
+func TestProcessMinAge(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+
+	now := time.Now()
+	repo := RepositoryConfig{Name: "author/name", Destination: "chan", MinAge: 72 * time.Hour}
+	releases := []release.Release{{Version: "1.0.0", Tag: "v1.0.0", PublishedAt: now.AddDate(0, -1, 0)}}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-notifications
+
+	releases = append([]release.Release{
+		{Version: "1.2.1", Tag: "v1.2.1", PublishedAt: now.Add(-time.Hour)},
+		{Version: "1.2.0", Tag: "v1.2.0", PublishedAt: now.Add(-80 * time.Hour)},
+	}, releases...)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 0 {
+		t.Fatalf("expected no notifications while 1.2.1 is young, got %d", len(notifications))
+	}
+
+	record, err := s.Store.Get("author/name")
+	if err != nil || record.Version != "1.0.0" || !slices.Equal(record.Pending, []string{"v1.2.1", "v1.2.0"}) {
+		t.Fatalf("expected 1.0.0 with pending releases, got %+v, %v", record, err)
+	}
+
+	releases[0].PublishedAt = now.Add(-73 * time.Hour)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if r := <-notifications; r.Version != "1.2.1" {
+		t.Fatalf("expected 1.2.1, got %s", r.Version)
+	}
+}
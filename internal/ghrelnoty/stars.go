@@ -0,0 +1,87 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// GitHubStarsProvider lists the repositories starred or watched by a GitHub user.
+// Star lists are not supported, as they are not exposed by the REST API.
+type GitHubStarsProvider struct {
+	ProviderConfig
+	Client *github.Client
+}
+
+func (p GitHubStarsProvider) Config() ProviderConfig {
+	return p.ProviderConfig
+}
+
+// Repositories returns the repositories starred (or watched, based on ProviderConfig.Source)
+// by ProviderConfig.User, following pagination.
+func (p GitHubStarsProvider) Repositories(ctx context.Context) ([]RepositoryConfig, error) {
+	var names []string
+	opts := github.ListOptions{PerPage: 100}
+	for {
+		var (
+			page []string
+			resp *github.Response
+			err  error
+		)
+
+		switch p.Source {
+		case "", "starred":
+			page, resp, err = p.listStarred(ctx, opts)
+		case "watched":
+			page, resp, err = p.listWatched(ctx, opts)
+		default:
+			return nil, fmt.Errorf("unknown source %s", p.Source)
+		}
+
+		if rateLimitErr := isRateLimited(err); rateLimitErr != nil {
+			return nil, rateLimitErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.User, err)
+		}
+
+		names = append(names, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	repos := make([]RepositoryConfig, 0, len(names))
+	for _, name := range names {
+		repos = append(repos, p.repository(name))
+	}
+	return repos, nil
+}
+
+func (p GitHubStarsProvider) listStarred(ctx context.Context, opts github.ListOptions) ([]string, *github.Response, error) {
+	starred, resp, err := p.Client.Activity.ListStarred(ctx, p.User, &github.ActivityListStarredOptions{ListOptions: opts})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	names := make([]string, 0, len(starred))
+	for _, s := range starred {
+		names = append(names, s.GetRepository().GetFullName())
+	}
+	return names, resp, nil
+}
+
+func (p GitHubStarsProvider) listWatched(ctx context.Context, opts github.ListOptions) ([]string, *github.Response, error) {
+	watched, resp, err := p.Client.Activity.ListWatched(ctx, p.User, &opts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	names := make([]string, 0, len(watched))
+	for _, r := range watched {
+		names = append(names, r.GetFullName())
+	}
+	return names, resp, nil
+}
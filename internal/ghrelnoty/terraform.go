@@ -0,0 +1,182 @@
+package ghrelnoty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/providers/terraform"
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// TerraformRepository gets the versions of a Terraform provider from its registry.
+// RepositoryConfig.Name is the source address of the provider, like
+// registry.terraform.io/hashicorp/aws, and RepositoryConfig.Registry optionally
+// overrides the URL of the registry API.
+type TerraformRepository struct {
+	RepositoryConfig
+	Client *http.Client
+}
+
+func (r TerraformRepository) Config() RepositoryConfig {
+	return r.RepositoryConfig
+}
+
+// GetReleases gets all the versions of the provider, newest first, so that it's
+// known how many releases a locked version is behind. Prereleases are filtered
+// according to RepositoryConfig.Prereleases, and only the latest version has its
+// publication time. There are no rate limits to report.
+func (r TerraformRepository) GetReleases(ctx context.Context) ([]release.Release, RateLimitData, error) {
+	parts := strings.Split(r.Name, "/")
+	if len(parts) != 3 {
+		return nil, RateLimitData{}, fmt.Errorf("%s: invalid provider address", r.Name)
+	}
+	host, namespace, typ := parts[0], parts[1], parts[2]
+
+	var list struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	}
+	if err := r.get(ctx, fmt.Sprintf("%s/%s/versions", namespace, typ), &list); err != nil {
+		return nil, RateLimitData{}, err
+	}
+
+	var versions []semver.Version
+	raw := make(map[semver.Version]string)
+	for _, v := range list.Versions {
+		parsed, err := semver.Parse(v.Version)
+		if err != nil || !r.WantsRelease(parsed.Prerelease != "") {
+			continue
+		}
+		versions = append(versions, parsed)
+		raw[parsed] = v.Version
+	}
+	slices.SortFunc(versions, func(a, b semver.Version) int {
+		return b.Compare(a)
+	})
+
+	releases := make([]release.Release, 0, len(versions))
+	for _, v := range versions {
+		version, err := r.Extract(raw[v], "")
+		if err != nil {
+			return nil, RateLimitData{}, fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		releases = append(releases, release.Release{
+			Kind:       release.KindNew,
+			Project:    typ,
+			Author:     host + "/" + namespace,
+			Version:    version,
+			Tag:        raw[v],
+			URL:        fmt.Sprintf("https://%s/providers/%s/%s/%s", host, namespace, typ, raw[v]),
+			Prerelease: v.Prerelease != "",
+		})
+	}
+
+	if len(releases) > 0 {
+		var details struct {
+			PublishedAt time.Time `json:"published_at"`
+		}
+		err := r.get(ctx, fmt.Sprintf("%s/%s/%s", namespace, typ, releases[0].Tag), &details)
+		if err != nil {
+			slog.DebugContext(ctx, "can't get provider version details", slog.String("repo", r.Name), slog.Any("err", err))
+		}
+		releases[0].PublishedAt = details.PublishedAt
+	}
+
+	return releases, RateLimitData{}, nil
+}
+
+// get decodes the response of the given endpoint of the providers API of the registry.
+func (r TerraformRepository) get(ctx context.Context, endpoint string, v any) error {
+	base := r.Registry
+	if base == "" {
+		host, _, _ := strings.Cut(r.Name, "/")
+		base = "https://" + host
+	}
+	url := fmt.Sprintf("%s/v1/providers/%s", strings.TrimSuffix(base, "/"), endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.Name, err)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.Name, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: GET %s: %s", r.Name, url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: read body: %w", r.Name, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%s: decode %s: %w", r.Name, url, err)
+	}
+	return nil
+}
+
+// TerraformProvider lists the providers locked in Terraform and OpenTofu
+// dependency lock files.
+type TerraformProvider struct {
+	ProviderConfig
+}
+
+func (p TerraformProvider) Config() ProviderConfig {
+	return p.ProviderConfig
+}
+
+// Repositories returns the providers locked in the .terraform.lock.hcl files under
+// ProviderConfig.Paths, with the files and versions locking them. They are watched
+// through their registry, or ProviderConfig.Registry if set.
+func (p TerraformProvider) Repositories(_ context.Context) ([]RepositoryConfig, error) {
+	var locks []terraform.Lock
+	for _, path := range p.Paths {
+		found, err := terraform.Find(path)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Path = filepath.ToSlash(filepath.Join(path, found[i].Path))
+		}
+		locks = append(locks, found...)
+	}
+
+	var repos []RepositoryConfig
+	byName := make(map[string]int)
+	for _, l := range locks {
+		name := strings.ToLower(l.Provider)
+
+		i, ok := byName[name]
+		if !ok {
+			i = len(repos)
+			byName[name] = i
+			repo := p.repository(name)
+			repo.Type = "terraform"
+			repo.Registry = p.Registry
+			repos = append(repos, repo)
+		}
+		repos[i].References = append(repos[i].References, release.Reference{
+			Path:    l.Path,
+			Version: l.Version,
+		})
+	}
+
+	return repos, nil
+}
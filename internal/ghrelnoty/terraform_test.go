@@ -0,0 +1,78 @@
+package ghrelnoty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestTerraformProvider(t *testing.T) {
+	p := TerraformProvider{
+		ProviderConfig: ProviderConfig{
+			Paths:       []string{"providers/terraform/testdata/infra"},
+			Destination: "email",
+		},
+	}
+
+	repos, err := p.Repositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repos) != 3 {
+		t.Fatalf("expected 3 repositories, got %d: %+v", len(repos), repos)
+	}
+
+	aws := repos[1]
+	if aws.Name != "registry.terraform.io/hashicorp/aws" || aws.Type != "terraform" || aws.Destination != "email" {
+		t.Fatalf("unexpected repository %+v", aws)
+	}
+	if len(aws.References) != 2 || aws.References[1].Version != "5.31.0" ||
+		aws.References[1].Path != "providers/terraform/testdata/infra/network/.terraform.lock.hcl" {
+		t.Fatalf("unexpected references %+v", aws.References)
+	}
+}
+
+func TestTerraformRepository(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/providers/hashicorp/aws/versions":
+			fmt.Fprint(w, `{"versions":[{"version":"5.31.0"},{"version":"5.40.0"},{"version":"5.32.1"},{"version":"6.0.0-beta1"}]}`)
+		case "/v1/providers/hashicorp/aws/5.40.0":
+			fmt.Fprint(w, `{"version":"5.40.0","published_at":"2024-03-07T20:10:16Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer registry.Close()
+
+	repo := TerraformRepository{
+		RepositoryConfig: RepositoryConfig{
+			Type:     "terraform",
+			Name:     "registry.terraform.io/hashicorp/aws",
+			Registry: registry.URL,
+		},
+		Client: registry.Client(),
+	}
+
+	releases, _, err := repo.GetReleases(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(releases) != 3 || releases[0].Version != "5.40.0" || releases[0].PublishedAt.IsZero() {
+		t.Fatalf("unexpected releases %+v", releases)
+	}
+	if releases[0].URL != "https://registry.terraform.io/providers/hashicorp/aws/5.40.0" {
+		t.Fatalf("unexpected URL %s", releases[0].URL)
+	}
+
+	refs := RepositoryConfig{References: []release.Reference{{Version: "5.31.0"}}}.ReferencesFor(releases[0], releases)
+	if !refs[0].Outdated || refs[0].Behind != 2 {
+		t.Fatalf("unexpected references %+v", refs)
+	}
+}
@@ -0,0 +1,76 @@
+package ghrelnoty
+
+import (
+	"fmt"
+
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// Thresholds decide which new releases are worth a notification, comparing versions
+// as semver. Constraint is a range of versions to notify, like >=2.0.0 <3.0.0, and
+// NotifyOn is the minimum bump compared to the previous release: major, minor or
+// patch (default). Releases below the thresholds still update the stored baseline.
+type Thresholds struct {
+	Constraint string `yaml:"constraint"`
+	NotifyOn   string `yaml:"notify_on"`
+
+	constraint *semver.Constraint
+}
+
+// bumpLevels ranks the values of NotifyOn.
+var bumpLevels = map[string]int{
+	"patch": 0,
+	"minor": 1,
+	"major": 2,
+}
+
+// Validate returns an error if the Thresholds can't be used, and parses their
+// Constraint otherwise. It must be called before Notifies.
+func (t *Thresholds) Validate() error {
+	if t.Constraint != "" {
+		constraint, err := semver.ParseConstraint(t.Constraint)
+		if err != nil {
+			return fmt.Errorf("invalid constraint: %w", err)
+		}
+		t.constraint = &constraint
+	}
+	if _, ok := bumpLevels[t.NotifyOn]; t.NotifyOn != "" && !ok {
+		return fmt.Errorf("invalid notify_on value %s", t.NotifyOn)
+	}
+	return nil
+}
+
+// Notifies returns true if the release meets the thresholds, given the version of
+// the release before it. Releases that aren't semver don't satisfy a Constraint,
+// nor does any release if it's not validated, but they are notified if the bump
+// can't be determined.
+func (t Thresholds) Notifies(r release.Release, previous string) bool {
+	v, err := semver.Parse(r.Version)
+	if t.Constraint != "" {
+		if err != nil || t.constraint == nil || !t.constraint.Check(v) {
+			return false
+		}
+	}
+
+	if t.NotifyOn == "" || t.NotifyOn == "patch" {
+		return true
+	}
+	p, perr := semver.Parse(previous)
+	if err != nil || perr != nil {
+		return true
+	}
+	return bump(p, v) >= bumpLevels[t.NotifyOn]
+}
+
+// bump returns the level of the change from the previous version to v.
+func bump(previous semver.Version, v semver.Version) int {
+	switch {
+	case v.Major != previous.Major:
+		return bumpLevels["major"]
+	case v.Minor != previous.Minor:
+		return bumpLevels["minor"]
+	default:
+		return bumpLevels["patch"]
+	}
+}
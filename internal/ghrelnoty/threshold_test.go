@@ -0,0 +1,83 @@
+package ghrelnoty
+
+import (
+	"context"
+	"testing"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestThresholdsNotifies(t *testing.T) {
+	cases := []struct {
+		thresholds Thresholds
+		version    string
+		previous   string
+		expected   bool
+	}{
+		{Thresholds{}, "v1.0.1", "v1.0.0", true},
+		{Thresholds{NotifyOn: "major"}, "v1.1.0", "v1.0.0", false},
+		{Thresholds{NotifyOn: "major"}, "v2.0.0", "v1.9.0", true},
+		{Thresholds{NotifyOn: "minor"}, "v1.0.1", "v1.0.0", false},
+		{Thresholds{NotifyOn: "minor"}, "v1.1.0", "v1.0.3", true},
+		{Thresholds{NotifyOn: "major"}, "v1.1.0", "Spring Release", true},
+		{Thresholds{Constraint: ">=2.0.0 <3.0.0"}, "v2.4.0", "v2.3.0", true},
+		{Thresholds{Constraint: ">=2.0.0 <3.0.0"}, "v3.0.0", "v2.4.0", false},
+		{Thresholds{Constraint: ">=2.0.0"}, "nightly", "v2.4.0", false},
+	}
+
+	for _, c := range cases {
+		if err := c.thresholds.Validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := c.thresholds.Notifies(release.Release{Version: c.version}, c.previous); got != c.expected {
+			t.Errorf("%+v, %s after %s: expected %t, got %t", c.thresholds, c.version, c.previous, c.expected, got)
+		}
+	}
+
+	if err := (&Thresholds{NotifyOn: "build"}).Validate(); err == nil {
+		t.Error("expected error for invalid notify_on")
+	}
+	if err := (&Thresholds{Constraint: ">=two"}).Validate(); err == nil {
+		t.Error("expected error for invalid constraint")
+	}
+	if (Thresholds{Constraint: ">=2.0.0"}).Notifies(release.Release{Version: "v2.4.0"}, "v2.3.0") {
+		t.Error("expected a constraint not validated not to be satisfied")
+	}
+}
+
+func TestProcessThresholdsUpdateBaseline(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+
+	repo := RepositoryConfig{
+		Name:        "author/name",
+		Destination: "chan",
+		Thresholds:  Thresholds{NotifyOn: "major"},
+	}
+	releases := []release.Release{{Version: "1.0.0", Tag: "v1.0.0"}}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-notifications
+
+	releases = append([]release.Release{{Version: "1.1.0", Tag: "v1.1.0"}}, releases...)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 0 {
+		t.Fatalf("expected no notifications for a minor bump, got %d", len(notifications))
+	}
+
+	record, err := s.Store.Get("author/name")
+	if err != nil || record.Version != "1.1.0" {
+		t.Fatalf("expected baseline 1.1.0, got %+v, %v", record, err)
+	}
+
+	releases = append([]release.Release{{Version: "2.0.0", Tag: "v2.0.0"}}, releases...)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := <-notifications; r.Version != "2.0.0" {
+		t.Fatalf("expected 2.0.0 to be notified, got %s", r.Version)
+	}
+}
@@ -0,0 +1,104 @@
+package ghrelnoty
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// Track is a release line of a repository, like the LTS 1.x and the current 2.x,
+// with its own stored baseline and destination. Releases belong to the track if
+// their version has the major (or major.minor) given as Line, or if Regex matches
+// their tag, name or version.
+type Track struct {
+	Name        string `yaml:"name"`
+	Line        string `yaml:"line"`
+	Regex       string `yaml:"regex"`
+	Destination string `yaml:"destination"`
+
+	regex *regexp.Regexp
+}
+
+var lineRegex = regexp.MustCompile(`^v?\d+(\.\d+)?$`)
+
+// Validate returns an error if the Track can't be used, and compiles its Regex
+// otherwise. It must be called before Matches.
+func (t *Track) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("track without name")
+	}
+	if (t.Line == "") == (t.Regex == "") {
+		return fmt.Errorf("track %s needs either line or regex", t.Name)
+	}
+	if t.Line != "" && !lineRegex.MatchString(t.Line) {
+		return fmt.Errorf("invalid line %s for track %s, expected major or major.minor", t.Line, t.Name)
+	}
+	if t.Regex != "" {
+		re, err := regexp.Compile(t.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex %s for track %s: %w", t.Regex, t.Name, err)
+		}
+		t.regex = re
+	}
+	return nil
+}
+
+// Matches returns true if the release belongs to the Track.
+func (t Track) Matches(r release.Release) bool {
+	if t.Regex != "" {
+		return t.regex != nil && matcher(r)(t.regex)
+	}
+
+	v, err := semver.Parse(r.Version)
+	if err != nil {
+		return false
+	}
+	major, minor, hasMinor := strings.Cut(strings.TrimPrefix(t.Line, "v"), ".")
+	if strconv.Itoa(v.Major) != major {
+		return false
+	}
+	return !hasMinor || strconv.Itoa(v.Minor) == minor
+}
+
+// Filter returns the releases that belong to the Track, keeping their order.
+func (t Track) Filter(releases []release.Release) []release.Release {
+	var found []release.Release
+	for _, r := range releases {
+		if t.Matches(r) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// validateTracks returns an error if any of the tracks can't be used, or if
+// their names are not unique.
+func validateTracks(tracks []Track) error {
+	names := make(map[string]bool, len(tracks))
+	for i := range tracks {
+		t := &tracks[i]
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		if names[t.Name] {
+			return fmt.Errorf("duplicate track %s", t.Name)
+		}
+		names[t.Name] = true
+	}
+	return nil
+}
+
+// ForTrack returns the configuration of the repository restricted to the given track.
+func (r RepositoryConfig) ForTrack(t Track) RepositoryConfig {
+	repo := r
+	repo.Tracks = nil
+	repo.Track = t.Name
+	if t.Destination != "" {
+		repo.Destination = t.Destination
+	}
+	return repo
+}
@@ -0,0 +1,91 @@
+package ghrelnoty
+
+import (
+	"context"
+	"testing"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestTrackMatches(t *testing.T) {
+	cases := []struct {
+		track    Track
+		version  string
+		expected bool
+	}{
+		{Track{Name: "lts", Line: "1"}, "1.9.5", true},
+		{Track{Name: "lts", Line: "1"}, "2.1.0", false},
+		{Track{Name: "lts", Line: "v1.4"}, "1.4.2", true},
+		{Track{Name: "lts", Line: "1.4"}, "1.5.0", false},
+		{Track{Name: "edge", Regex: `-(alpha|beta)`}, "2.2.0-beta.1", true},
+		{Track{Name: "lts", Line: "1"}, "nightly", false},
+	}
+
+	for _, c := range cases {
+		if err := c.track.Validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := c.track.Matches(release.Release{Version: c.version}); got != c.expected {
+			t.Errorf("%+v, %s: expected %t, got %t", c.track, c.version, c.expected, got)
+		}
+	}
+
+	invalid := [][]Track{
+		{{Line: "1"}},
+		{{Name: "lts"}},
+		{{Name: "lts", Line: "1.x"}},
+		{{Name: "lts", Line: "1", Regex: "^1"}},
+		{{Name: "lts", Line: "1"}, {Name: "lts", Line: "2"}},
+	}
+	for _, tracks := range invalid {
+		if err := validateTracks(tracks); err == nil {
+			t.Errorf("expected error for %+v", tracks)
+		}
+	}
+}
+
+func TestProcessTracks(t *testing.T) {
+	s, notifiers := newTestService(t, "email", "ops")
+	email := notifiers["email"]
+	ops := notifiers["ops"]
+
+	repo := RepositoryConfig{
+		Name:        "author/name",
+		Destination: "email",
+		Tracks: []Track{
+			{Name: "lts", Line: "1", Destination: "ops"},
+			{Name: "current", Line: "2"},
+		},
+	}
+	releases := []release.Release{
+		{Version: "2.1.0", Tag: "v2.1.0"},
+		{Version: "1.9.4", Tag: "v1.9.4"},
+	}
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := <-ops; r.Version != "1.9.4" || r.Track != "lts" {
+		t.Fatalf("expected 1.9.4 on the lts track, got %+v", r)
+	}
+	if r := <-email; r.Version != "2.1.0" || r.Track != "current" {
+		t.Fatalf("expected 2.1.0 on the current track, got %+v", r)
+	}
+
+	releases = append([]release.Release{{Version: "1.9.5", Tag: "v1.9.5"}}, releases...)
+	if err := s.process(context.Background(), repo, releases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ops) != 1 || len(email) != 0 {
+		t.Fatalf("expected only the lts patch to be notified, got %d and %d", len(ops), len(email))
+	}
+	if r := <-ops; r.Version != "1.9.5" {
+		t.Fatalf("expected 1.9.5, got %+v", r)
+	}
+
+	for key, version := range map[string]string{"author/name#lts": "1.9.5", "author/name#current": "2.1.0"} {
+		record, err := s.Store.Get(key)
+		if err != nil || record.Version != version {
+			t.Errorf("expected %s for %s, got %+v, %v", version, key, record, err)
+		}
+	}
+}
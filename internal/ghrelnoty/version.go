@@ -0,0 +1,124 @@
+package ghrelnoty
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"it.davquar/gitrelnoty/pkg/release"
+	"it.davquar/gitrelnoty/pkg/semver"
+)
+
+// VersionConfig describes how to extract a normalized version from a release.
+// Source is one of tag, name or auto (default), in which case the tag is used if
+// it contains a digit and the name otherwise. Regex, if set, is applied to the source
+// and its first capture group (or the whole match) is kept; then the first matching
+// prefix among StripPrefixes is removed.
+type VersionConfig struct {
+	Source        string   `yaml:"version_source"`
+	Regex         string   `yaml:"version_regex"`
+	StripPrefixes []string `yaml:"strip_prefixes"`
+
+	regex *regexp.Regexp
+}
+
+var digitRegex = regexp.MustCompile(`\d`)
+
+// Validate returns an error if the VersionConfig can't be used, and compiles its
+// Regex otherwise. It must be called before Extract.
+func (v *VersionConfig) Validate() error {
+	switch v.Source {
+	case "", "auto", "tag", "name":
+	default:
+		return fmt.Errorf("invalid version source %s", v.Source)
+	}
+
+	re, err := regexp.Compile(v.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid version regex %s: %w", v.Regex, err)
+	}
+	v.regex = re
+	return nil
+}
+
+// Extract returns the normalized version, given the tag and the name of a release.
+// If the regex doesn't match, the source is used as is.
+func (v VersionConfig) Extract(tag string, name string) (string, error) {
+	var version string
+	switch v.Source {
+	case "tag":
+		version = tag
+	case "name":
+		version = name
+	default:
+		version = tag
+		if !digitRegex.MatchString(tag) && name != "" {
+			version = name
+		}
+	}
+	version = strings.TrimSpace(version)
+
+	if v.Regex != "" {
+		if v.regex == nil {
+			return "", errors.New("version regex not validated")
+		}
+
+		if match := v.regex.FindStringSubmatch(version); match != nil {
+			version = match[0]
+			if len(match) > 1 {
+				version = match[1]
+			}
+		}
+	}
+
+	for _, prefix := range v.StripPrefixes {
+		if strings.HasPrefix(version, prefix) {
+			version = strings.TrimPrefix(version, prefix)
+			break
+		}
+	}
+
+	return version, nil
+}
+
+// isOutdated returns true if the used version is older than the latest one. A used
+// version that is a prefix of the latest one, component by component and ignoring a
+// leading v, is not outdated: v4 and v4.2 are not compared to v4.2.0, while v3 and
+// v4.1.0 are. Otherwise, versions are compared as semver if both can be parsed, so
+// that v5 is not outdated compared to v4.2.0.
+func isOutdated(used string, latest string) bool {
+	u := strings.Split(strings.TrimPrefix(used, "v"), ".")
+	l := strings.Split(strings.TrimPrefix(latest, "v"), ".")
+	if len(u) <= len(l) && slices.Equal(u, l[:len(u)]) {
+		return false
+	}
+
+	if c, err := semver.Compare(used, latest); err == nil {
+		return c < 0
+	}
+	return true
+}
+
+// releasesBehind returns the number of releases newer than the used version, up to
+// the given one, or 0 if versions can't be compared as semver.
+func releasesBehind(used string, rel release.Release, releases []release.Release) int {
+	u, err := semver.Parse(used)
+	if err != nil {
+		return 0
+	}
+	latest, err := semver.Parse(rel.Version)
+	if err != nil {
+		return 0
+	}
+
+	behind := 0
+	for _, r := range releases {
+		v, err := semver.Parse(r.Version)
+		if err == nil && v.Compare(u) > 0 && v.Compare(latest) <= 0 {
+			behind++
+		}
+	}
+	return behind
+}
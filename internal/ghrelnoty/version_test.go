@@ -0,0 +1,90 @@
+package ghrelnoty
+
+import (
+	"testing"
+
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestVersionExtract(t *testing.T) {
+	cases := []struct {
+		config   VersionConfig
+		tag      string
+		name     string
+		expected string
+	}{
+		{VersionConfig{}, "v1.2.3", "Spring Release 🌸", "v1.2.3"},
+		{VersionConfig{}, "stable", "Release 2", "Release 2"},
+		{VersionConfig{Source: "name"}, "v1.2.3", "1.2.3 ", "1.2.3"},
+		{VersionConfig{Source: "tag", StripPrefixes: []string{"release-", "v"}}, "release-1.2.3", "", "1.2.3"},
+		{VersionConfig{StripPrefixes: []string{"v"}}, "v1.2.3", "", "1.2.3"},
+		{VersionConfig{Regex: `^app/v(.+)$`}, "app/v1.2.3", "", "1.2.3"},
+		{VersionConfig{Regex: `\d+\.\d+`}, "build-1.2-final", "", "1.2"},
+		{VersionConfig{Regex: `^app/v(.+)$`}, "other/v1", "", "other/v1"},
+	}
+
+	for _, c := range cases {
+		if err := c.config.Validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := c.config.Extract(c.tag, c.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != c.expected {
+			t.Errorf("%+v (%s, %s): expected %s, got %s", c.config, c.tag, c.name, c.expected, got)
+		}
+	}
+}
+
+func TestVersionValidate(t *testing.T) {
+	if err := (&VersionConfig{Source: "title"}).Validate(); err == nil {
+		t.Fatal("expected error for unknown source")
+	}
+	if err := (&VersionConfig{Regex: "("}).Validate(); err == nil {
+		t.Fatal("expected error for invalid regex")
+	}
+	if err := (&VersionConfig{Source: "tag", Regex: `v(.+)`}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIsOutdated(t *testing.T) {
+	cases := []struct {
+		used     string
+		latest   string
+		expected bool
+	}{
+		{"v4", "v4.2.0", false},
+		{"v4.2", "v4.2.0", false},
+		{"v4.2.0", "4.2.0", false},
+		{"v3", "v4.2.0", true},
+		{"v4.1.0", "v4.2.0", true},
+		{"v4.2.0.1", "v4.2.0", true},
+		{"v5", "v4.2.0", false},
+		{"v4.3.0", "v4.2.0", false},
+		{"main", "v4.2.0", true},
+	}
+
+	for _, c := range cases {
+		if got := isOutdated(c.used, c.latest); got != c.expected {
+			t.Errorf("%s compared to %s: expected %t, got %t", c.used, c.latest, c.expected, got)
+		}
+	}
+}
+
+func TestReleasesBehind(t *testing.T) {
+	releases := []release.Release{
+		{Version: "v5.40.0"}, {Version: "v5.33.0"}, {Version: "v5.32.1"}, {Version: "v5.31.0"}, {Version: "nightly"},
+	}
+
+	if got := releasesBehind("5.31.0", releases[0], releases); got != 3 {
+		t.Errorf("expected 3 releases behind, got %d", got)
+	}
+	if got := releasesBehind("5.31.0", releases[1], releases); got != 2 {
+		t.Errorf("expected 2 releases behind the older release, got %d", got)
+	}
+	if got := releasesBehind("main", releases[0], releases); got != 0 {
+		t.Errorf("expected 0 releases behind for a branch, got %d", got)
+	}
+}
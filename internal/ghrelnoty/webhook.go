@@ -0,0 +1,147 @@
+package ghrelnoty
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"it.davquar/gitrelnoty/internal/metrics"
+	"it.davquar/gitrelnoty/internal/store"
+)
+
+// deliveriesRetention is how long the IDs of webhook deliveries are kept, to ignore
+// duplicates: GitHub redeliveries are possible for 3 days.
+const deliveriesRetention = 7 * 24 * time.Hour
+
+// WebhookHandler returns the handler for GitHub webhook deliveries. The HMAC-SHA256
+// signature of each delivery is verified against Config.WebhookSecret, and deliveries
+// already received are ignored. Release events of watched repositories trigger a check
+// of the repository, in the background: if it fails, the delivery is forgotten, so that
+// GitHub can redeliver it.
+func (s Service) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		payload, err := github.ValidatePayloadFromBody(r.Header.Get("Content-Type"), r.Body,
+			r.Header.Get(github.SHA256SignatureHeader), []byte(s.Config.WebhookSecret))
+		if err != nil {
+			metrics.WebhookError()
+			slog.Warn("invalid webhook delivery", slog.Any("err", err))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		deliveryID := github.DeliveryID(r)
+		event, err := github.ParseWebHook(github.WebHookType(r), payload)
+		if err != nil {
+			metrics.WebhookError()
+			slog.Warn("can't parse webhook delivery", slog.String("delivery", deliveryID), slog.Any("err", err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if deliveryID != "" {
+			seen, err := s.Store.MarkSeen(store.DeliveriesBucket, deliveryID)
+			if err != nil {
+				metrics.DBError()
+				slog.Error("can't store webhook delivery", slog.String("delivery", deliveryID), slog.Any("err", err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if seen {
+				slog.Debug("duplicate webhook delivery", slog.String("delivery", deliveryID))
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+
+		releaseEvent, ok := event.(*github.ReleaseEvent)
+		if !ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		name := releaseEvent.GetRepo().GetFullName()
+		slog.Info("got release event", slog.String("repo", name), slog.String("action", releaseEvent.GetAction()), slog.String("delivery", deliveryID))
+		w.WriteHeader(http.StatusAccepted)
+
+		go func() {
+			if err := s.Check(context.Background(), name); err != nil {
+				slog.Error("can't check repository from webhook", slog.String("repo", name), slog.Any("err", err))
+				s.forgetDelivery(deliveryID)
+			}
+		}()
+	})
+}
+
+// forgetDelivery removes the given delivery ID from the ones received, if any.
+func (s Service) forgetDelivery(deliveryID string) {
+	if deliveryID == "" {
+		return
+	}
+	if err := s.Store.UnmarkSeen(store.DeliveriesBucket, deliveryID); err != nil {
+		metrics.DBError()
+		slog.Error("can't forget webhook delivery", slog.String("delivery", deliveryID), slog.Any("err", err))
+	}
+}
+
+// Check gets the releases of the watched repository with the given name, and
+// processes them like Work does, never at the same time as Work for the same repository.
+func (s Service) Check(ctx context.Context, name string) error {
+	repo, ok := s.findReleaser(ctx, name)
+	if !ok {
+		return errors.New("repository not watched")
+	}
+
+	releases, _, err := repo.GetReleases(ctx)
+	if err != nil {
+		metrics.CannotGetRelease()
+		return err
+	}
+
+	unlock := s.locks.lock(repo.Config().Name)
+	defer unlock()
+	return s.process(ctx, repo.Config(), releases)
+}
+
+// findReleaser returns the Releaser of the watched repository with the given name,
+// among the ones listed by the last Work, if any.
+func (s Service) findReleaser(ctx context.Context, name string) (Releaser, bool) {
+	releasers := s.watched.get()
+	if releasers == nil {
+		releasers = s.releasers(ctx)
+		s.watched.set(releasers)
+	}
+
+	for _, r := range releasers {
+		if strings.EqualFold(r.Config().Name, name) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// pruneDeliveries removes the IDs of the webhook deliveries received more than
+// deliveriesRetention ago, as GitHub doesn't redeliver them anymore.
+func (s Service) pruneDeliveries() {
+	if s.Config.WebhookSecret == "" {
+		return
+	}
+
+	pruned, err := s.Store.Prune(store.DeliveriesBucket, time.Now().Add(-deliveriesRetention))
+	if err != nil {
+		metrics.DBError()
+		slog.Error("can't prune webhook deliveries", slog.Any("err", err))
+		return
+	}
+	if pruned > 0 {
+		slog.Debug("pruned webhook deliveries", slog.Int("count", pruned))
+	}
+}
@@ -0,0 +1,195 @@
+package ghrelnoty
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/destinations"
+	"it.davquar/gitrelnoty/internal/store"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+type chanNotifier chan release.Release
+
+func (c chanNotifier) Notify(n destinations.Notification) error {
+	c <- n.Release
+	return nil
+}
+
+func signedRequest(secret string, deliveryID string, body string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "release")
+	req.Header.Set("X-GitHub-Delivery", deliveryID)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestWebhookHandler(t *testing.T) {
+	s, err := New(Config{
+		DBPath:        filepath.Join(t.TempDir(), "ghrelnoty.db"),
+		WebhookSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	defer s.Close()
+
+	notifications := make(chanNotifier, 1)
+	s.Releasers = []Releaser{
+		dummyReleaser{RepositoryConfig{Name: "author/name", Destination: "chan"}},
+	}
+	s.Notifiers = map[string]Notifier{"chan": notifications}
+
+	body := `{"action": "published", "repository": {"full_name": "author/name"}}`
+	handler := s.WebhookHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest("wrong", "1", body))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for wrong signature, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest("secret", "2", body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+
+	select {
+	case r := <-notifications:
+		if r.Version != "v1.2.3" {
+			t.Fatalf("expected release v1.2.3, got %s", r.Version)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a notification")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest("secret", "2", body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for duplicate delivery, got %d", rec.Code)
+	}
+}
+
+func TestWebhookUnparsedDeliveryNotSeen(t *testing.T) {
+	s, err := New(Config{
+		DBPath:        filepath.Join(t.TempDir(), "ghrelnoty.db"),
+		WebhookSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	defer s.Close()
+
+	rec := httptest.NewRecorder()
+	s.WebhookHandler().ServeHTTP(rec, signedRequest("secret", "1", "{"))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid payload, got %d", rec.Code)
+	}
+
+	seen, err := s.Store.MarkSeen(store.DeliveriesBucket, "1")
+	if err != nil || seen {
+		t.Fatalf("expected delivery not to be marked as seen, got %t, %v", seen, err)
+	}
+}
+
+func TestWebhookFailedCheckNotSeen(t *testing.T) {
+	s, err := New(Config{
+		DBPath:        filepath.Join(t.TempDir(), "ghrelnoty.db"),
+		WebhookSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	defer s.Close()
+
+	// The notifier is missing, so the check fails.
+	s.Releasers = []Releaser{
+		dummyReleaser{RepositoryConfig{Name: "author/name", Destination: "chan"}},
+	}
+	s.Notifiers = map[string]Notifier{}
+
+	body := `{"action": "published", "repository": {"full_name": "author/name"}}`
+	rec := httptest.NewRecorder()
+	s.WebhookHandler().ServeHTTP(rec, signedRequest("secret", "1", body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		seen, err := s.Store.Seen(store.DeliveriesBucket, "1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !seen {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the delivery to be forgotten after the failed check")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec = httptest.NewRecorder()
+	s.WebhookHandler().ServeHTTP(rec, signedRequest("secret", "1", body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected the redelivery to be accepted, got %d", rec.Code)
+	}
+}
+
+type countingProvider struct {
+	dummyProvider
+	calls *atomic.Int32
+}
+
+func (p countingProvider) Repositories(ctx context.Context) ([]RepositoryConfig, error) {
+	p.calls.Add(1)
+	return p.dummyProvider.Repositories(ctx)
+}
+
+func TestCheckSerialized(t *testing.T) {
+	s, notifiers := newTestService(t, "chan")
+	notifications := notifiers["chan"]
+
+	calls := &atomic.Int32{}
+	s.Providers = []Provider{countingProvider{
+		dummyProvider{[]RepositoryConfig{{Type: "github", Name: "author/name", Destination: "chan"}}},
+		calls,
+	}}
+	s.Releasers = []Releaser{}
+	s.watched.set([]Releaser{dummyReleaser{RepositoryConfig{Name: "author/name", Destination: "chan"}}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Check(context.Background(), "Author/Name"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("expected the cached releasers to be used, got %d provider calls", n)
+	}
+}
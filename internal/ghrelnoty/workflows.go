@@ -0,0 +1,121 @@
+package ghrelnoty
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"it.davquar/gitrelnoty/internal/ghrelnoty/providers/workflows"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+// WorkflowsProvider lists the GitHub Actions used by the workflows and composite
+// actions of local repository checkouts.
+type WorkflowsProvider struct {
+	ProviderConfig
+	Client *github.Client
+
+	// resolved caches the resolvedTags of SHA-pinned actions, by repository.
+	resolved *sync.Map
+}
+
+// resolvedTagsTTL is how long the tags of the repository of a SHA-pinned action are
+// cached, as the pinned commits of existing tags rarely change.
+const resolvedTagsTTL = 24 * time.Hour
+
+// resolvedTags holds the most specific tag of the commits of a repository, by SHA,
+// until they expire.
+type resolvedTags struct {
+	tags    map[string]string
+	expires time.Time
+}
+
+func (p WorkflowsProvider) Config() ProviderConfig {
+	return p.ProviderConfig
+}
+
+var commentVersionRegex = regexp.MustCompile(`v?\d+(\.\d+)*\S*`)
+
+// Repositories returns the repositories of the GitHub Actions referenced in the
+// checkouts at ProviderConfig.Paths, with the files and versions referencing them.
+// The version of SHA-pinned references is taken from their comment, or resolved
+// through the tags of the action's repository.
+func (p WorkflowsProvider) Repositories(ctx context.Context) ([]RepositoryConfig, error) {
+	var usages []workflows.Usage
+	for _, path := range p.Paths {
+		found, err := workflows.Find(path)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Path = filepath.ToSlash(filepath.Join(path, found[i].Path))
+		}
+		usages = append(usages, found...)
+	}
+
+	var repos []RepositoryConfig
+	byName := make(map[string]int)
+	for _, u := range usages {
+		version := p.version(ctx, u)
+
+		i, ok := byName[u.Repo]
+		if !ok {
+			i = len(repos)
+			byName[u.Repo] = i
+			repos = append(repos, p.repository(u.Repo))
+		}
+		repos[i].References = append(repos[i].References, release.Reference{
+			Path:    u.Path,
+			Version: version,
+		})
+	}
+
+	return repos, nil
+}
+
+// version returns the version referenced by the usage, resolving SHA-pinned
+// references without a version comment through the tags of the repository,
+// preferring the most specific tag.
+func (p WorkflowsProvider) version(ctx context.Context, u workflows.Usage) string {
+	if !u.IsPinned() {
+		return u.Ref
+	}
+	if v := commentVersionRegex.FindString(u.Comment); v != "" {
+		return v
+	}
+
+	if tag, ok := p.tags(ctx, u.Repo)[u.Ref]; ok {
+		return tag
+	}
+	return u.Ref
+}
+
+// tags returns the most specific tag of the commits of the given repository, by SHA.
+// Tags are cached for resolvedTagsTTL, unless they can't be listed.
+func (p WorkflowsProvider) tags(ctx context.Context, name string) map[string]string {
+	if cached, ok := p.resolved.Load(name); ok {
+		if r, ok := cached.(resolvedTags); ok && time.Now().Before(r.expires) {
+			return r.tags
+		}
+	}
+
+	author, repo := RepositoryConfig{Name: name}.SeparateName()
+	commits, err := tagCommits(ctx, p.Client, author, repo)
+	if err != nil {
+		slog.WarnContext(ctx, "can't resolve pinned action", slog.String("repo", name), slog.Any("err", err))
+		return nil
+	}
+
+	tags := make(map[string]string, len(commits))
+	for tag, sha := range commits {
+		if _, exists := tags[sha]; !exists || len(tag) > len(tags[sha]) {
+			tags[sha] = tag
+		}
+	}
+	p.resolved.Store(name, resolvedTags{tags: tags, expires: time.Now().Add(resolvedTagsTTL)})
+	return tags
+}
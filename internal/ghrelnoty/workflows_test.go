@@ -0,0 +1,57 @@
+package ghrelnoty
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"it.davquar/gitrelnoty/internal/ghrelnoty/providers/workflows"
+	"it.davquar/gitrelnoty/pkg/release"
+)
+
+func TestWorkflowsProvider(t *testing.T) {
+	p := WorkflowsProvider{
+		ProviderConfig: ProviderConfig{
+			Paths:       []string{"providers/workflows/testdata/checkout"},
+			Destination: "email",
+		},
+		resolved: &sync.Map{},
+	}
+
+	repos, err := p.Repositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repos) != 4 {
+		t.Fatalf("expected 4 repositories, got %d: %v", len(repos), repos)
+	}
+
+	setupGo := repos[1]
+	if setupGo.Name != "actions/setup-go" || setupGo.Destination != "email" {
+		t.Fatalf("unexpected repository %+v", setupGo)
+	}
+
+	refs := setupGo.ReferencesFor(release.Release{Version: "v5.5.0"}, nil)
+	if len(refs) != 1 || refs[0].Version != "v5.4.0" || !refs[0].Outdated ||
+		refs[0].Path != "providers/workflows/testdata/checkout/.github/workflows/ci.yaml" {
+		t.Fatalf("unexpected references %+v", refs)
+	}
+}
+
+func TestWorkflowsProviderResolvedTags(t *testing.T) {
+	sha := strings.Repeat("a", 40)
+	p := WorkflowsProvider{resolved: &sync.Map{}}
+	p.resolved.Store("actions/checkout", resolvedTags{
+		tags:    map[string]string{sha: "v4.2.2"},
+		expires: time.Now().Add(time.Hour),
+	})
+
+	// The client is nil: the tags have to come from the cache.
+	u := workflows.Usage{Repo: "actions/checkout", Ref: sha}
+	if got := p.version(context.Background(), u); got != "v4.2.2" {
+		t.Fatalf("expected v4.2.2 from the cached tags, got %s", got)
+	}
+}
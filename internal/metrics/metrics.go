@@ -61,6 +61,66 @@ var notificationErrorsCounter = promauto.NewCounter(prometheus.CounterOpts{
 	Help:      "Total times there were problems notifying",
 })
 
+var providerErrorsCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "provider_errors_total",
+	Help:      "Total times it was not possible to list the repositories of a provider",
+})
+
+var releaseMutationsCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "release_mutations_total",
+	Help:      "Total times an announced release was retagged, edited or deleted",
+})
+
+var webhookErrorsCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "webhook_errors_total",
+	Help:      "Total times a webhook delivery was rejected",
+})
+
+var advisoriesFoundCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "advisories_found_total",
+	Help:      "Total times a new security advisory was found",
+})
+
+var lifecycleEventsCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "lifecycle_events_total",
+	Help:      "Total times a repository was archived, renamed, transferred or deprecated",
+})
+
+var releasesSuppressedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "releases_suppressed_total",
+	Help:      "Total times a release was excluded by filters",
+})
+
+var backportsFoundCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "backports_found_total",
+	Help:      "Total times a release lower than the latest one was published after it",
+})
+
+var notificationsMutedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "notifications_muted_total",
+	Help:      "Total times a notification was silenced by a mute, snooze or ignored version",
+})
+
+var versionsBehindGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "versions_behind",
+	Help:      "Number of releases newer than the deployed version, by level (major, minor, patch)",
+}, []string{"repository", "track", "level"})
+
+var daysBehindGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "days_since_superseded",
+	Help:      "Days since the deployed version was superseded by a newer release",
+}, []string{"repository", "track"})
+
 func DBOpenError() {
 	dbOpenErrorsCounter.Inc()
 }
@@ -85,6 +145,13 @@ func SetRateLimitUsedValue(value float64) {
 	rateLimitUsedGauge.Set(value)
 }
 
+func SetVersionsBehind(repository string, track string, major int, minor int, patch int, days int) {
+	versionsBehindGauge.WithLabelValues(repository, track, "major").Set(float64(major))
+	versionsBehindGauge.WithLabelValues(repository, track, "minor").Set(float64(minor))
+	versionsBehindGauge.WithLabelValues(repository, track, "patch").Set(float64(patch))
+	daysBehindGauge.WithLabelValues(repository, track).Set(float64(days))
+}
+
 func CannotGetRelease() {
 	releaseGetErrorsCounter.Inc()
 }
@@ -96,3 +163,35 @@ func NewReleaseFound() {
 func NotificationError() {
 	notificationErrorsCounter.Inc()
 }
+
+func ProviderError() {
+	providerErrorsCounter.Inc()
+}
+
+func ReleaseMutated() {
+	releaseMutationsCounter.Inc()
+}
+
+func WebhookError() {
+	webhookErrorsCounter.Inc()
+}
+
+func AdvisoryFound() {
+	advisoriesFoundCounter.Inc()
+}
+
+func LifecycleEvent() {
+	lifecycleEventsCounter.Inc()
+}
+
+func ReleaseSuppressed() {
+	releasesSuppressedCounter.Inc()
+}
+
+func BackportFound() {
+	backportsFoundCounter.Inc()
+}
+
+func NotificationMuted() {
+	notificationsMutedCounter.Inc()
+}
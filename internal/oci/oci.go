@@ -0,0 +1,278 @@
+// Package oci implements the parts of the OCI distribution API needed to list
+// the tags of an image and read its labels, with anonymous token authentication.
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SourceLabel is the label linking an image to the repository of its source code.
+const SourceLabel = "org.opencontainers.image.source"
+
+const manifestTypes = "application/vnd.oci.image.index.v1+json, " +
+	"application/vnd.docker.distribution.manifest.list.v2+json, " +
+	"application/vnd.oci.image.manifest.v1+json, " +
+	"application/vnd.docker.distribution.manifest.v2+json"
+
+// Client talks to OCI registries. Tokens are cached by repository.
+type Client struct {
+	HTTP *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewClient returns a Client using the given http.Client.
+func NewClient(client *http.Client) *Client {
+	return &Client{
+		HTTP:   client,
+		tokens: make(map[string]string),
+	}
+}
+
+// Tags returns all the tags of the repository in the registry, following pagination.
+func (c *Client) Tags(ctx context.Context, registry string, repository string) ([]string, error) {
+	var tags []string
+	next := fmt.Sprintf("%s/v2/%s/tags/list", baseURL(registry), repository)
+	for next != "" {
+		resp, err := c.get(ctx, registry, repository, next, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = decode(resp, &page)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Tags...)
+
+		next, err = nextPage(resp, next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// Labels returns the labels of the image with the given tag. For multi-platform
+// images, the labels of the linux/amd64 image (or the first one) are returned.
+func (c *Client) Labels(ctx context.Context, registry string, repository string, tag string) (map[string]string, error) {
+	var manifest struct {
+		Manifests []struct {
+			Digest   string `json:"digest"`
+			Platform struct {
+				OS           string `json:"os"`
+				Architecture string `json:"architecture"`
+			} `json:"platform"`
+		} `json:"manifests"`
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}
+
+	base := fmt.Sprintf("%s/v2/%s", baseURL(registry), repository)
+	resp, err := c.get(ctx, registry, repository, base+"/manifests/"+tag, manifestTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(resp, &manifest); err != nil {
+		return nil, err
+	}
+
+	if len(manifest.Manifests) > 0 {
+		digest := manifest.Manifests[0].Digest
+		for _, m := range manifest.Manifests {
+			if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				digest = m.Digest
+				break
+			}
+		}
+
+		resp, err := c.get(ctx, registry, repository, base+"/manifests/"+digest, manifestTypes)
+		if err != nil {
+			return nil, err
+		}
+		if err := decode(resp, &manifest); err != nil {
+			return nil, err
+		}
+	}
+	if manifest.Config.Digest == "" {
+		return nil, fmt.Errorf("%s/%s:%s: manifest without config", registry, repository, tag)
+	}
+
+	resp, err = c.get(ctx, registry, repository, base+"/blobs/"+manifest.Config.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}
+	if err := decode(resp, &config); err != nil {
+		return nil, err
+	}
+	return config.Config.Labels, nil
+}
+
+// get performs a GET request, authenticating with an anonymous token if the
+// registry asks for one.
+func (c *Client) get(ctx context.Context, registry string, repository string, url string, accept string) (*http.Response, error) {
+	key := registry + "/" + repository
+	resp, err := c.do(ctx, url, accept, c.token(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return checkStatus(resp)
+	}
+
+	challenge := resp.Header.Get("Www-Authenticate")
+	_ = resp.Body.Close()
+	token, err := c.authenticate(ctx, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	c.mu.Lock()
+	c.tokens[key] = token
+	c.mu.Unlock()
+
+	resp, err = c.do(ctx, url, accept, token)
+	if err != nil {
+		return nil, err
+	}
+	return checkStatus(resp)
+}
+
+func (c *Client) do(ctx context.Context, url string, accept string, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.HTTP.Do(req)
+}
+
+func (c *Client) token(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens[key]
+}
+
+var challengeRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate gets an anonymous token as described by a Bearer challenge like
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull".
+func (c *Client) authenticate(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication %q", challenge)
+	}
+
+	query := url.Values{}
+	var realm string
+	for _, match := range challengeRegex.FindAllStringSubmatch(params, -1) {
+		if match[1] == "realm" {
+			realm = match[2]
+			continue
+		}
+		query.Set(match[1], match[2])
+	}
+	if realm == "" {
+		return "", fmt.Errorf("authentication challenge without realm %q", challenge)
+	}
+
+	resp, err := c.do(ctx, realm+"?"+query.Encode(), "", "")
+	if err != nil {
+		return "", err
+	}
+	resp, err = checkStatus(resp)
+	if err != nil {
+		return "", err
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := decode(resp, &token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// baseURL returns the URL of the registry API, using the actual host for Docker Hub.
+func baseURL(registry string) string {
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+	}
+	if strings.HasPrefix(registry, "localhost") || strings.HasPrefix(registry, "127.0.0.1") {
+		return "http://" + registry
+	}
+	return "https://" + registry
+}
+
+var linkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPage returns the URL of the next page from the Link header of the response,
+// resolved against the current URL, or an empty string.
+func nextPage(resp *http.Response, current string) (string, error) {
+	match := linkRegex.FindStringSubmatch(resp.Header.Get("Link"))
+	if match == nil {
+		return "", nil
+	}
+
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	next, err := base.Parse(match[1])
+	if err != nil {
+		return "", fmt.Errorf("parse next page: %w", err)
+	}
+	return next.String(), nil
+}
+
+func checkStatus(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", resp.Request.URL, resp.Status)
+	}
+	return resp, nil
+}
+
+func decode(resp *http.Response, v any) error {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", resp.Request.URL, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode %s: %w", resp.Request.URL, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newRegistry(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:library/nginx:pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token":"secret"}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("Www-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:library/nginx:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/library/nginx/tags/list":
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/library/nginx/tags/list?last=latest&n=3>; rel="next"`)
+				fmt.Fprint(w, `{"tags":["1.26.2","1.27.0","latest"]}`)
+				return
+			}
+			fmt.Fprint(w, `{"tags":["1.28.0"]}`)
+		case "/v2/library/nginx/manifests/1.27.0":
+			fmt.Fprint(w, `{"manifests":[
+				{"digest":"sha256:arm","platform":{"os":"linux","architecture":"arm64"}},
+				{"digest":"sha256:amd","platform":{"os":"linux","architecture":"amd64"}}]}`)
+		case "/v2/library/nginx/manifests/sha256:amd":
+			fmt.Fprint(w, `{"config":{"digest":"sha256:cfg"}}`)
+		case "/v2/library/nginx/blobs/sha256:cfg":
+			fmt.Fprint(w, `{"config":{"Labels":{"org.opencontainers.image.source":"https://github.com/nginx/nginx"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTags(t *testing.T) {
+	server := newRegistry(t)
+	client := NewClient(server.Client())
+
+	tags, err := client.Tags(context.Background(), strings.TrimPrefix(server.URL, "http://"), "library/nginx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(tags, []string{"1.26.2", "1.27.0", "latest", "1.28.0"}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	server := newRegistry(t)
+	client := NewClient(server.Client())
+
+	labels, err := client.Labels(context.Background(), strings.TrimPrefix(server.URL, "http://"), "library/nginx", "1.27.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if labels[SourceLabel] != "https://github.com/nginx/nginx" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+}
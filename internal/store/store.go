@@ -1,7 +1,12 @@
 package store
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -10,14 +15,45 @@ import (
 // is stored on Bolt.
 const ReleasesBucket string = "releases"
 
+// DeliveriesBucket is the name of the bucket in which the IDs of the
+// received webhook deliveries are stored on Bolt.
+const DeliveriesBucket string = "deliveries"
+
+// AdvisoriesBucket is the name of the bucket in which the IDs of the
+// security advisories already seen for each repository are stored on Bolt.
+const AdvisoriesBucket string = "advisories"
+
+// MetadataBucket is the name of the bucket in which the Metadata of each
+// repository is stored on Bolt.
+const MetadataBucket string = "metadata"
+
+// RenamesBucket is the name of the bucket in which the new names of renamed
+// or transferred repositories are stored on Bolt, by their previous name.
+const RenamesBucket string = "renames"
+
+// BackportsBucket is the name of the bucket in which the backports already
+// reported are stored on Bolt, by repository and tag.
+const BackportsBucket string = "backports"
+
+// StatusBucket is the name of the bucket in which the Status of each repository
+// with a deployed version is stored on Bolt.
+const StatusBucket string = "status"
+
+// MutesBucket is the name of the bucket in which the Mute of each repository
+// is stored on Bolt.
+const MutesBucket string = "mutes"
+
+const openTimeout = 5 * time.Second
+
 // Store holds the instance to the Bolt database.
 type Store struct {
 	DB *bolt.DB
 }
 
-// Open opens (creating it if needed) a new Bolt database file.
+// Open opens (creating it if needed) a new Bolt database file. It fails if the file
+// stays locked by another process for openTimeout.
 func Open(path string) (Store, error) {
-	db, err := bolt.Open(path, 0600, nil)
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
 	if err != nil {
 		return Store{}, fmt.Errorf("cannot open db: %w", err)
 	}
@@ -29,35 +65,88 @@ func (s *Store) Close() error {
 	return s.DB.Close()
 }
 
-// Get returns the value of the given key from the database.
-func (s *Store) Get(key string) (string, error) {
+// Record holds the data stored for each repository: the normalized version of
+// the latest known release, its raw tag, publication time, the commit SHA of the tag
+// and the digests of its assets by name. Pending holds the tags of newer releases
+// that are not notified yet, because they are waiting for their assets or to be old
+// enough, with Detected holding the time the latter were first seen, and
+// Suppressed the tags of releases excluded by filters, so that they don't resurface.
+// Watched holds the Fingerprints of the other announced releases, to detect their
+// mutations too.
+type Record struct {
+	Version     string               `json:"version"`
+	Tag         string               `json:"tag,omitempty"`
+	PublishedAt time.Time            `json:"published_at"`
+	Commit      string               `json:"commit,omitempty"`
+	Assets      map[string]string    `json:"assets,omitempty"`
+	Pending     []string             `json:"pending,omitempty"`
+	Detected    map[string]time.Time `json:"detected,omitempty"`
+	Suppressed  []string             `json:"suppressed,omitempty"`
+	Watched     []Fingerprint        `json:"watched,omitempty"`
+
+	// Legacy is true if the record was stored by older versions as a plain string,
+	// which was the release name.
+	Legacy bool `json:"-"`
+}
+
+// Fingerprint identifies the content of an announced release, to detect its
+// mutations: the commit SHA of its tag and the digests of its assets, by name.
+type Fingerprint struct {
+	Version     string            `json:"version"`
+	Tag         string            `json:"tag"`
+	PublishedAt time.Time         `json:"published_at"`
+	Commit      string            `json:"commit,omitempty"`
+	Assets      map[string]string `json:"assets,omitempty"`
+}
+
+// Get returns the Record of the given key from the database. The zero Record
+// is returned if the key doesn't exist.
+func (s *Store) Get(key string) (Record, error) {
 	var value []byte
 	err := s.DB.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(ReleasesBucket))
+		if b == nil {
+			return nil
+		}
 		value = b.Get([]byte(key))
 		return nil
 	})
+	if err != nil {
+		return Record{}, err
+	}
 
-	return string(value), err
+	return decodeRecord(value)
 }
 
-// CompareAndSet writes the given value for the given key in the database, if the new
-// and current values are different, returning true if the change has been done.
-func (s *Store) CompareAndSet(key string, value string) (bool, error) {
+// CompareAndSet writes the given Record for the given key in the database, if it is
+// different from the current one, returning true if the version has changed.
+func (s *Store) CompareAndSet(key string, record Record) (bool, error) {
 	var changed bool
 	err := s.DB.Update(func(tx *bolt.Tx) error {
-		b, _ := tx.CreateBucketIfNotExists([]byte(ReleasesBucket))
-		current := b.Get([]byte(key))
+		b, err := tx.CreateBucketIfNotExists([]byte(ReleasesBucket))
+		if err != nil {
+			return fmt.Errorf("create bucket: %w", err)
+		}
+
+		stored := b.Get([]byte(key))
+		current, err := decodeRecord(stored)
+		if err != nil {
+			return err
+		}
 
-		if string(current) == value {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+		if bytes.Equal(stored, value) {
 			return nil
 		}
 
-		err := b.Put([]byte(key), []byte(value))
+		err = b.Put([]byte(key), value)
 		if err != nil {
 			return fmt.Errorf("put: %w", err)
 		}
-		changed = true
+		changed = current.Version != record.Version
 
 		return nil
 	})
@@ -65,18 +154,467 @@ func (s *Store) CompareAndSet(key string, value string) (bool, error) {
 	return changed, err
 }
 
-// Set writes the given value for the given key in the database.
-func (s *Store) Set(key string, value string) error {
+// Set writes the given Record for the given key in the database.
+func (s *Store) Set(key string, record Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	return s.put(ReleasesBucket, key, value)
+}
+
+// Metadata holds what is known about a repository, besides its releases.
+type Metadata struct {
+	FullName      string `json:"full_name"`
+	Archived      bool   `json:"archived"`
+	DefaultBranch string `json:"default_branch"`
+	Description   string `json:"description"`
+	Deprecated    bool   `json:"deprecated"`
+}
+
+// Status holds how far the deployed version of a repository is from its latest
+// release, as of the last check.
+type Status struct {
+	Current    string    `json:"current_version"`
+	Latest     string    `json:"latest_version"`
+	Major      int       `json:"major"`
+	Minor      int       `json:"minor"`
+	Patch      int       `json:"patch"`
+	Superseded time.Time `json:"superseded"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// SetStatus writes the given Status for the given key in the database.
+func (s *Store) SetStatus(key string, status Status) error {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return s.put(StatusBucket, key, value)
+}
+
+// ListStatus returns the Status of all the repositories, by key.
+func (s *Store) ListStatus() (map[string]Status, error) {
+	statuses := make(map[string]Status)
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(StatusBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k []byte, v []byte) error {
+			var status Status
+			if err := json.Unmarshal(v, &status); err != nil {
+				return fmt.Errorf("unmarshal %s: %w", k, err)
+			}
+			statuses[string(k)] = status
+			return nil
+		})
+	})
+	return statuses, err
+}
+
+// GetMetadata returns the Metadata of the given key from the database, and false
+// if the key doesn't exist.
+func (s *Store) GetMetadata(key string) (Metadata, bool, error) {
+	var value []byte
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(MetadataBucket))
+		if b == nil {
+			return nil
+		}
+		value = b.Get([]byte(key))
+		return nil
+	})
+	if err != nil || value == nil {
+		return Metadata{}, false, err
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(value, &metadata); err != nil {
+		return Metadata{}, false, fmt.Errorf("unmarshal: %w", err)
+	}
+	return metadata, true, nil
+}
+
+// SetMetadata writes the given Metadata for the given key in the database.
+func (s *Store) SetMetadata(key string, metadata Metadata) error {
+	value, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return s.put(MetadataBucket, key, value)
+}
+
+// renamedBuckets are the buckets whose keys are, or start with, the key of a
+// repository, followed by # for its tracks or / for its items.
+var renamedBuckets = []string{ReleasesBucket, StatusBucket, BackportsBucket, AdvisoriesBucket, MetadataBucket, MutesBucket}
+
+// Rename moves all the data of the given key to the new key, together with the one
+// of its tracks (stored as key#track) and items (stored as key/item), and remembers
+// the new key, so that ResolveKey returns it for the previous one.
+func (s *Store) Rename(from string, to string) error {
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range renamedBuckets {
+			b := tx.Bucket([]byte(bucket))
+			if b == nil {
+				continue
+			}
+			f, t := from, to
+			if bucket == MutesBucket {
+				f, t = string(muteKey(from)), string(muteKey(to))
+			}
+			if err := renameKeys(b, f, t); err != nil {
+				return fmt.Errorf("rename in %s: %w", bucket, err)
+			}
+		}
+
+		renames, err := tx.CreateBucketIfNotExists([]byte(RenamesBucket))
+		if err != nil {
+			return fmt.Errorf("create bucket: %w", err)
+		}
+		if err := renames.Put([]byte(from), []byte(to)); err != nil {
+			return fmt.Errorf("put: %w", err)
+		}
+		return nil
+	})
+}
+
+// renameKeys moves the values of the given key, and of the keys starting with it
+// followed by # or /, under the new key, keeping the ones already there.
+func renameKeys(b *bolt.Bucket, from string, to string) error {
+	keys := [][]byte{[]byte(from)}
+	for _, separator := range []string{"#", "/"} {
+		prefix := []byte(from + separator)
+		c := b.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+		}
+	}
+
+	for _, key := range keys {
+		value := b.Get(key)
+		if value == nil {
+			continue
+		}
+		newKey := []byte(to + strings.TrimPrefix(string(key), from))
+		if b.Get(newKey) == nil {
+			if err := b.Put(newKey, bytes.Clone(value)); err != nil {
+				return fmt.Errorf("put: %w", err)
+			}
+		}
+		if err := b.Delete(key); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolveKey returns the key under which the data of the given key is stored,
+// following renames.
+func (s *Store) ResolveKey(key string) (string, error) {
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(RenamesBucket))
+		if b == nil {
+			return nil
+		}
+		for i := 0; i < 10; i++ {
+			to := b.Get([]byte(key))
+			if to == nil {
+				return nil
+			}
+			key = string(to)
+		}
+		return nil
+	})
+	return key, err
+}
+
+// Mute holds how the notifications of a repository are silenced: all of them while
+// Muted or before Until, and the ones of the Ignored versions or tags.
+type Mute struct {
+	Muted   bool      `json:"muted,omitempty"`
+	Until   time.Time `json:"until"`
+	Ignored []string  `json:"ignored,omitempty"`
+}
+
+// Silences returns true if the notifications for the given version or tag are
+// silenced at the given time.
+func (m Mute) Silences(version string, tag string, now time.Time) bool {
+	if m.Muted || now.Before(m.Until) {
+		return true
+	}
+	return (version != "" && slices.Contains(m.Ignored, version)) || (tag != "" && slices.Contains(m.Ignored, tag))
+}
+
+// GetMute returns the Mute of the given key, case-insensitively, from the database,
+// removing its snooze if it ended. The zero Mute is returned if the key doesn't exist.
+func (s *Store) GetMute(key string) (Mute, error) {
+	var mute Mute
 	err := s.DB.Update(func(tx *bolt.Tx) error {
-		b, err := tx.CreateBucketIfNotExists([]byte(ReleasesBucket))
+		b := tx.Bucket([]byte(MutesBucket))
+		if b == nil {
+			return nil
+		}
+		var err error
+		mute, err = readMute(b, muteKey(key), time.Now())
+		return err
+	})
+	return mute, err
+}
+
+// ListMutes returns the Mute of all the repositories, by lowercase key, removing
+// the snoozes that ended.
+func (s *Store) ListMutes() (map[string]Mute, error) {
+	mutes := make(map[string]Mute)
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(MutesBucket))
+		if b == nil {
+			return nil
+		}
+
+		var keys [][]byte
+		err := b.ForEach(func(k []byte, _ []byte) error {
+			keys = append(keys, bytes.Clone(k))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, k := range keys {
+			mute, err := readMute(b, k, now)
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			if !mute.empty() {
+				mutes[string(k)] = mute
+			}
+		}
+		return nil
+	})
+	return mutes, err
+}
+
+// Mute silences all the notifications of the given key until the given time, or
+// until Unmute if it's zero.
+func (s *Store) Mute(key string, until time.Time) error {
+	return s.updateMute(key, func(m *Mute) {
+		m.Muted = until.IsZero()
+		m.Until = until
+	})
+}
+
+// Unmute stops silencing all the notifications of the given key, keeping the
+// ignored versions.
+func (s *Store) Unmute(key string) error {
+	return s.updateMute(key, func(m *Mute) {
+		m.Muted = false
+		m.Until = time.Time{}
+	})
+}
+
+// Ignore silences the notifications of the given key for the given version or tag.
+func (s *Store) Ignore(key string, version string) error {
+	return s.updateMute(key, func(m *Mute) {
+		if !slices.Contains(m.Ignored, version) {
+			m.Ignored = append(m.Ignored, version)
+		}
+	})
+}
+
+// Unignore stops silencing the notifications of the given key for the given
+// version or tag.
+func (s *Store) Unignore(key string, version string) error {
+	return s.updateMute(key, func(m *Mute) {
+		m.Ignored = slices.DeleteFunc(m.Ignored, func(v string) bool {
+			return v == version
+		})
+	})
+}
+
+// updateMute applies the given update to the Mute of the given key in a single
+// transaction, deleting it if nothing is silenced anymore.
+func (s *Store) updateMute(key string, update func(*Mute)) error {
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(MutesBucket))
+		if err != nil {
+			return fmt.Errorf("create bucket: %w", err)
+		}
+
+		k := muteKey(key)
+		mute, err := readMute(b, k, time.Now())
+		if err != nil {
+			return err
+		}
+		update(&mute)
+		return putMute(b, k, mute)
+	})
+}
+
+// muteKey returns the key of the Mute of a repository: names are case-insensitive,
+// as they are on GitHub.
+func muteKey(key string) []byte {
+	return []byte(strings.ToLower(key))
+}
+
+// empty returns true if the Mute doesn't silence anything.
+func (m Mute) empty() bool {
+	return !m.Muted && m.Until.IsZero() && len(m.Ignored) == 0
+}
+
+// readMute returns the Mute of the given key in the given bucket, removing its snooze
+// if it ended before the given time, and the whole Mute if nothing is silenced anymore.
+func readMute(b *bolt.Bucket, key []byte, now time.Time) (Mute, error) {
+	value := b.Get(key)
+	if value == nil {
+		return Mute{}, nil
+	}
+
+	var mute Mute
+	if err := json.Unmarshal(value, &mute); err != nil {
+		return Mute{}, fmt.Errorf("unmarshal: %w", err)
+	}
+	if mute.Until.IsZero() || now.Before(mute.Until) {
+		return mute, nil
+	}
+
+	mute.Until = time.Time{}
+	return mute, putMute(b, key, mute)
+}
+
+// putMute writes the given Mute for the given key in the given bucket, deleting it
+// if nothing is silenced.
+func putMute(b *bolt.Bucket, key []byte, mute Mute) error {
+	if mute.empty() {
+		if err := b.Delete(key); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		return nil
+	}
+
+	value, err := json.Marshal(mute)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	if err := b.Put(key, value); err != nil {
+		return fmt.Errorf("put: %w", err)
+	}
+	return nil
+}
+
+// put writes the given value for the given key in the given bucket.
+func (s *Store) put(bucket string, key string, value []byte) error {
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return fmt.Errorf("create bucket: %w", err)
+		}
+		err = b.Put([]byte(key), value)
+		if err != nil {
+			return fmt.Errorf("put: %w", err)
+		}
+		return nil
+	})
+}
+
+// Seen returns true if the given key was recorded in the given bucket by MarkSeen.
+func (s *Store) Seen(bucket string, key string) (bool, error) {
+	var seen bool
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		seen = b != nil && b.Get([]byte(key)) != nil
+		return nil
+	})
+	return seen, err
+}
+
+// MarkSeen records the given key in the given bucket, with the current time,
+// returning true if it was already there.
+func (s *Store) MarkSeen(bucket string, key string) (bool, error) {
+	var seen bool
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
 		if err != nil {
 			return fmt.Errorf("create bucket: %w", err)
 		}
-		err = b.Put([]byte(key), []byte(value))
+
+		if b.Get([]byte(key)) != nil {
+			seen = true
+			return nil
+		}
+
+		err = b.Put([]byte(key), []byte(time.Now().UTC().Format(time.RFC3339)))
 		if err != nil {
 			return fmt.Errorf("put: %w", err)
 		}
 		return nil
 	})
-	return err
+	return seen, err
+}
+
+// UnmarkSeen removes the given key from the given bucket, so that MarkSeen records
+// it again.
+func (s *Store) UnmarkSeen(bucket string, key string) error {
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		if err := b.Delete([]byte(key)); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		return nil
+	})
+}
+
+// Prune removes from the given bucket the keys recorded by MarkSeen before the given
+// time, returning how many were removed.
+func (s *Store) Prune(bucket string, before time.Time) (int, error) {
+	pruned := 0
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		var keys [][]byte
+		err := b.ForEach(func(k []byte, v []byte) error {
+			seen, err := time.Parse(time.RFC3339, string(v))
+			if err == nil && seen.Before(before) {
+				keys = append(keys, bytes.Clone(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+		pruned = len(keys)
+		return nil
+	})
+	return pruned, err
+}
+
+// decodeRecord returns the Record encoded in value, handling legacy plain strings.
+func decodeRecord(value []byte) (Record, error) {
+	if len(value) == 0 {
+		return Record{}, nil
+	}
+
+	if !bytes.HasPrefix(value, []byte("{")) {
+		return Record{Version: string(value), Legacy: true}, nil
+	}
+
+	var record Record
+	if err := json.Unmarshal(value, &record); err != nil {
+		return Record{}, fmt.Errorf("unmarshal: %w", err)
+	}
+	return record, nil
 }
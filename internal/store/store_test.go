@@ -0,0 +1,264 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTemp(t *testing.T) Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "ghrelnoty.db"))
+	if err != nil {
+		t.Fatalf("cannot open db: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return s
+}
+
+func TestGetMissing(t *testing.T) {
+	s := openTemp(t)
+
+	r, err := s.Get("author/repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Version != "" || r.Tag != "" || r.Legacy {
+		t.Fatalf("expected empty record, got %+v", r)
+	}
+}
+
+func TestCompareAndSet(t *testing.T) {
+	s := openTemp(t)
+
+	changed, err := s.CompareAndSet("author/repo", Record{Version: "1.2.3", Tag: "v1.2.3"})
+	if err != nil || !changed {
+		t.Fatalf("expected change without errors, got %t, %v", changed, err)
+	}
+
+	changed, err = s.CompareAndSet("author/repo", Record{Version: "1.2.3", Tag: "v1.2.3"})
+	if err != nil || changed {
+		t.Fatalf("expected no change without errors, got %t, %v", changed, err)
+	}
+
+	r, err := s.Get("author/repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Version != "1.2.3" || r.Tag != "v1.2.3" {
+		t.Fatalf("unexpected record %+v", r)
+	}
+}
+
+func TestLegacyRecord(t *testing.T) {
+	s := openTemp(t)
+
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(ReleasesBucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("author/repo"), []byte("Release 1.2.3"))
+	})
+	if err != nil {
+		t.Fatalf("cannot write legacy value: %v", err)
+	}
+
+	r, err := s.Get("author/repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.Legacy || r.Version != "Release 1.2.3" {
+		t.Fatalf("expected legacy record, got %+v", r)
+	}
+
+	changed, err := s.CompareAndSet("author/repo", Record{Version: "1.2.3", Tag: "v1.2.3"})
+	if err != nil || !changed {
+		t.Fatalf("expected change without errors, got %t, %v", changed, err)
+	}
+
+	r, err = s.Get("author/repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Legacy {
+		t.Fatal("expected record to be migrated")
+	}
+}
+
+func TestRenameWithTracks(t *testing.T) {
+	s := openTemp(t)
+
+	for key, version := range map[string]string{"author/repo": "2.1.0", "author/repo#lts": "1.9.5", "author/repository": "0.1.0"} {
+		if err := s.Set(key, Record{Version: version}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := s.Rename("author/repo", "author/new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, version := range map[string]string{"author/new": "2.1.0", "author/new#lts": "1.9.5", "author/repo#lts": "", "author/repository": "0.1.0"} {
+		r, err := s.Get(key)
+		if err != nil || r.Version != version {
+			t.Errorf("expected %q for %s, got %+v, %v", version, key, r, err)
+		}
+	}
+}
+
+func TestRenameMovesAllData(t *testing.T) {
+	s := openTemp(t)
+
+	if err := s.SetStatus("author/repo#lts", Status{Current: "1.9.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.SetMetadata("author/repo", Metadata{FullName: "author/new"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Mute("author/repo", time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"author/repo/v1.9.5", "author/repository/v0.1.0"} {
+		if _, err := s.MarkSeen(BackportsBucket, key); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := s.Rename("author/repo", "author/new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses, err := s.ListStatus()
+	if err != nil || len(statuses) != 1 || statuses["author/new#lts"].Current != "1.9.0" {
+		t.Errorf("expected the status to be moved, got %v, %v", statuses, err)
+	}
+	if m, ok, err := s.GetMetadata("author/new"); err != nil || !ok || m.FullName != "author/new" {
+		t.Errorf("expected the metadata to be moved, got %+v, %v", m, err)
+	}
+	if m, err := s.GetMute("author/new"); err != nil || !m.Muted {
+		t.Errorf("expected the mute to be moved, got %+v, %v", m, err)
+	}
+	for key, expected := range map[string]bool{"author/new/v1.9.5": true, "author/repo/v1.9.5": false, "author/repository/v0.1.0": true} {
+		if seen, err := s.Seen(BackportsBucket, key); err != nil || seen != expected {
+			t.Errorf("expected %s seen to be %t, got %t, %v", key, expected, seen, err)
+		}
+	}
+}
+
+func TestMutes(t *testing.T) {
+	s := openTemp(t)
+	now := time.Now()
+
+	if err := s.Mute("author/repo", now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Ignore("author/repo", "1.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m, err := s.GetMute("author/repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !m.Silences("2.0.0", "v2.0.0", now) || m.Silences("2.0.0", "v2.0.0", now.Add(2*time.Hour)) {
+		t.Fatalf("expected a snooze for one hour, got %+v", m)
+	}
+	if !m.Silences("1.2.3", "v1.2.3", now.Add(2*time.Hour)) {
+		t.Fatalf("expected 1.2.3 to be ignored, got %+v", m)
+	}
+
+	if err := s.Unmute("author/repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Unignore("author/repo", "1.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mutes, err := s.ListMutes()
+	if err != nil || len(mutes) != 0 {
+		t.Fatalf("expected no mutes left, got %+v, %v", mutes, err)
+	}
+}
+
+func TestMutesCaseInsensitive(t *testing.T) {
+	s := openTemp(t)
+
+	if err := s.Mute("Author/Repo", time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m, err := s.GetMute("author/repo"); err != nil || !m.Muted {
+		t.Fatalf("expected author/repo to be muted, got %+v, %v", m, err)
+	}
+	if err := s.Unmute("AUTHOR/REPO"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mutes, err := s.ListMutes(); err != nil || len(mutes) != 0 {
+		t.Fatalf("expected no mutes left, got %+v, %v", mutes, err)
+	}
+}
+
+func TestMutesPruneEndedSnoozes(t *testing.T) {
+	s := openTemp(t)
+	ended := time.Now().Add(-time.Hour)
+
+	if err := s.Mute("author/snoozed", ended); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Mute("author/ignored", ended); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Ignore("author/ignored", "1.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mutes, err := s.ListMutes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mutes) != 1 || !mutes["author/ignored"].Until.IsZero() || len(mutes["author/ignored"].Ignored) != 1 {
+		t.Fatalf("expected only the ignored version to be left, got %+v", mutes)
+	}
+
+	if err := s.DB.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(MutesBucket)).Get([]byte("author/snoozed")) != nil {
+			t.Error("expected the ended snooze to be deleted")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := openTemp(t)
+
+	if seen, err := s.Seen(DeliveriesBucket, "new"); err != nil || seen {
+		t.Fatalf("expected new not to be seen, got %t, %v", seen, err)
+	}
+	if _, err := s.MarkSeen(DeliveriesBucket, "new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	old := time.Now().Add(-10 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	if err := s.put(DeliveriesBucket, "old", []byte(old)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pruned, err := s.Prune(DeliveriesBucket, time.Now().Add(-7*24*time.Hour))
+	if err != nil || pruned != 1 {
+		t.Fatalf("expected 1 pruned key, got %d, %v", pruned, err)
+	}
+	for key, expected := range map[string]bool{"new": true, "old": false} {
+		if seen, err := s.Seen(DeliveriesBucket, key); err != nil || seen != expected {
+			t.Errorf("expected %s seen %t, got %t, %v", key, expected, seen, err)
+		}
+		if seen, err := s.MarkSeen(DeliveriesBucket, key); err != nil || seen != expected {
+			t.Errorf("expected %s seen %t, got %t, %v", key, expected, seen, err)
+		}
+	}
+}
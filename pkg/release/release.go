@@ -1,16 +1,165 @@
 package release
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// Kind tells what a notification about a release is for.
+type Kind string
+
+const (
+	// KindNew is used for newly published releases.
+	KindNew Kind = "new"
+	// KindMutated is used for already announced releases whose tag was moved,
+	// whose assets were replaced or removed, or that were deleted.
+	KindMutated Kind = "mutated"
+	// KindAdvisory is used for security advisories published for a repository.
+	KindAdvisory Kind = "advisory"
+	// KindLifecycle is used for repositories that were archived, renamed,
+	// transferred or deprecated.
+	KindLifecycle Kind = "lifecycle"
+	// KindBackport is used for releases with a version lower than the latest one,
+	// published after it.
+	KindBackport Kind = "backport"
+)
+
+// Priority tells how urgent a notification is, for destinations that support it.
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityHigh
+	PriorityUrgent
+)
+
+// ParsePriority returns the Priority with the given name: normal, high or urgent.
+func ParsePriority(s string) (Priority, error) {
+	switch s {
+	case "", "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	case "urgent":
+		return PriorityUrgent, nil
+	default:
+		return PriorityNormal, fmt.Errorf("invalid priority %s", s)
+	}
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityUrgent:
+		return "urgent"
+	default:
+		return "normal"
+	}
+}
 
 // Release holds data that describe a release.
+// Version is normalized, while Tag and Name are as published.
+// Commit is the SHA of the commit the tag points to, if known.
+// Changes describes what happened, for mutated releases and lifecycle events.
+// Track is the name of the release line the release belongs to, if configured.
+// Priority and Labels are set from the keywords found in the description.
 type Release struct {
+	Kind        Kind
 	Project     string
 	Author      string
 	Version     string
+	Tag         string
+	Name        string
+	Commit      string
 	Description string
 	URL         string
+	PublishedAt time.Time
+	Prerelease  bool
+	Assets      []Asset
+	Changes     []string
+	Advisory    *Advisory
+	References  []Reference
+	Behind      *Behind
+	Track       string
+	Priority    Priority
+	Labels      []string
+}
+
+// Reference is a file where a specific version of the project is in use, like
+// a workflow. Outdated is true if the version is older than the release, and
+// Behind is the number of releases between them, if known.
+type Reference struct {
+	Path     string
+	Version  string
+	Outdated bool
+	Behind   int
+}
+
+// Behind describes how far the deployed version of a project is from a release:
+// how many newer major, minor and patch releases there are, and when the deployed
+// version was first superseded, if known.
+type Behind struct {
+	Current    string
+	Major      int
+	Minor      int
+	Patch      int
+	Superseded time.Time
+}
+
+// Releases returns the number of releases newer than the deployed version.
+func (b Behind) Releases() int {
+	return b.Major + b.Minor + b.Patch
+}
+
+// Days returns the number of whole days between when the deployed version was
+// superseded and now, or 0 if unknown.
+func (b Behind) Days(now time.Time) int {
+	if b.Superseded.IsZero() {
+		return 0
+	}
+	return int(now.Sub(b.Superseded).Hours() / 24)
+}
+
+// Asset holds data that describe a file attached to a release.
+// Digest is in the form algorithm:hex, if known.
+type Asset struct {
+	Name        string
+	Size        int64
+	ContentType string
+	URL         string
+	Digest      string
 }
 
 func (r Release) Repo() string {
 	return fmt.Sprintf("%s/%s", r.Author, r.Project)
 }
+
+// Advisory holds data that describe a security advisory, like a GHSA.
+type Advisory struct {
+	ID              string
+	CVEs            []string
+	Severity        string
+	Summary         string
+	Vulnerabilities []Vulnerability
+}
+
+// Vulnerability holds the affected and patched version ranges of a package
+// mentioned in an Advisory.
+type Vulnerability struct {
+	Package  string
+	Affected string
+	Patched  string
+}
+
+// HasAsset returns true if the release has at least one asset whose name
+// matches the given glob pattern.
+func (r Release) HasAsset(pattern string) bool {
+	for _, a := range r.Assets {
+		if ok, _ := path.Match(pattern, a.Name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Constraint is a set of alternative ranges of versions, like >=2.0.0 <3.0.0 || ^4.1.
+// A Version satisfies the Constraint if it satisfies all the comparisons of at
+// least one range.
+type Constraint struct {
+	ranges [][]comparison
+}
+
+type comparison struct {
+	op      string
+	version Version
+}
+
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// ParseConstraint parses a constraint made of ranges separated by ||, each one
+// made of comparisons separated by spaces or commas. Comparisons are a version
+// preceded by one of =, !=, >, >=, <, <=, ~ (same minor, or same major if only
+// the major is given) or ^ (same major, or same minor for 0.x versions).
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	for _, alternative := range strings.Split(s, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(fields) == 0 {
+			return Constraint{}, fmt.Errorf("empty range in constraint %q", s)
+		}
+
+		var r []comparison
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			// Allow a space between the operator and the version, like >= 1.2.
+			if slices.Contains(operators, field) && i+1 < len(fields) {
+				i++
+				field += fields[i]
+			}
+
+			comparisons, err := parseComparison(field)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("constraint %q: %w", s, err)
+			}
+			r = append(r, comparisons...)
+		}
+		c.ranges = append(c.ranges, r)
+	}
+	return c, nil
+}
+
+// Check returns true if the version satisfies the Constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, r := range c.ranges {
+		ok := true
+		for _, comp := range r {
+			if !comp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComparison parses a single comparison, expanding ~ and ^ into a lower
+// and an upper bound.
+func parseComparison(s string) ([]comparison, error) {
+	op := ""
+	for _, o := range operators {
+		if strings.HasPrefix(s, o) {
+			op = o
+			break
+		}
+	}
+
+	raw := strings.TrimPrefix(s, op)
+	v, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	core, _, _ := strings.Cut(strings.TrimPrefix(raw, "v"), "-")
+	parts := len(strings.Split(core, "."))
+
+	switch op {
+	case "~":
+		upper := Version{Major: v.Major, Minor: v.Minor + 1}
+		if parts == 1 {
+			upper = Version{Major: v.Major + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	case "^":
+		upper := Version{Major: v.Major + 1}
+		switch {
+		case v.Major == 0 && (v.Minor > 0 || parts == 2):
+			upper = Version{Minor: v.Minor + 1}
+		case v.Major == 0 && parts == 3:
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	case "", "==":
+		op = "="
+	}
+	return []comparison{{op, v}}, nil
+}
+
+func (c comparison) check(v Version) bool {
+	result := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
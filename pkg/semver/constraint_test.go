@@ -0,0 +1,48 @@
+package semver
+
+import "testing"
+
+func TestConstraintCheck(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{">=2.0.0 <3.0.0", "2.5.1", true},
+		{">=2.0.0 <3.0.0", "3.0.0", false},
+		{">=2.0.0, <3.0.0", "1.9.9", false},
+		{">= 2.0.0", "v2.0.0", true},
+		{"!=1.2.3", "1.2.3", false},
+		{"1.2.3", "v1.2.3", true},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"^1.2", "1.9.0", true},
+		{"^1.2", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"<1.0.0 || >=2.0.0", "2.1.0", true},
+		{"<1.0.0 || >=2.0.0", "1.1.0", false},
+	}
+
+	for _, c := range cases {
+		constraint, err := ParseConstraint(c.constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.constraint, err)
+		}
+		v, err := Parse(c.version)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.version, err)
+		}
+		if got := constraint.Check(v); got != c.expected {
+			t.Errorf("%s against %s: expected %t, got %t", c.version, c.constraint, c.expected, got)
+		}
+	}
+
+	for _, s := range []string{"", ">=", "<1.0.0 ||", ">=latest"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := map[string]Version{
+		"v1.2.3":           {Major: 1, Minor: 2, Patch: 3},
+		"1.2.3-rc.1+build": {Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Build: "build"},
+		"v4":               {Major: 4},
+		"2.1":              {Major: 2, Minor: 1},
+	}
+
+	for s, expected := range cases {
+		v, err := Parse(s)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s, err)
+		}
+		if v != expected {
+			t.Errorf("%s: expected %+v, got %+v", s, expected, v)
+		}
+	}
+
+	for _, s := range []string{"", "latest", "1.2.3.4", "v1.x", "Spring Release"} {
+		if IsValid(s) {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.9.5",
+		"2.1.0",
+		"v10.0.0",
+	}
+
+	for i := 0; i+1 < len(ordered); i++ {
+		c, err := Compare(ordered[i], ordered[i+1])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c != -1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	if c, _ := Compare("v1.2.3+a", "1.2.3+b"); c != 0 {
+		t.Error("expected build metadata to be ignored")
+	}
+}